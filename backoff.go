@@ -0,0 +1,57 @@
+package discordgo
+
+import (
+	"math/rand"
+	"time"
+)
+
+type BackoffPolicy interface {
+	Backoff(attempt int) time.Duration
+}
+
+type ExponentialBackoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+func (b ExponentialBackoff) Backoff(attempt int) time.Duration {
+	if b.Base <= 0 || attempt < 1 {
+		return 0
+	}
+
+	ceiling := b.Max
+	if shift := uint(attempt - 1); shift < 32 {
+		if d := b.Base << shift; d > 0 && (b.Max <= 0 || d < b.Max) {
+			ceiling = d
+		}
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+type ConstantBackoff struct {
+	Delay time.Duration
+}
+
+func (b ConstantBackoff) Backoff(attempt int) time.Duration {
+	return b.Delay
+}
+
+type BackoffFunc func(attempt int) time.Duration
+
+func (f BackoffFunc) Backoff(attempt int) time.Duration {
+	return f(attempt)
+}
+
+var DefaultBackoff BackoffPolicy = ExponentialBackoff{Base: time.Second, Max: 600 * time.Second}
+
+func (s *Session) backoff(attempt int) time.Duration {
+	if s.ReconnectBackoff != nil {
+		return s.ReconnectBackoff.Backoff(attempt)
+	}
+
+	return DefaultBackoff.Backoff(attempt)
+}
@@ -44,11 +44,22 @@ func constCase(name string) string {
 
 func event(name string) bool {
 	options := map[string]bool{
-		"Connect":    false,
-		"Disconnect": false,
-		"Event":      false,
-		"RateLimit":  false,
-		"Interface":  false,
+		"Connect":               false,
+		"Disconnect":            false,
+		"Event":                 false,
+		"RateLimit":             false,
+		"Interface":             false,
+		"GatewayFatalClose":     false,
+		"ReconnectAttempt":      false,
+		"HeartbeatAckMissed":    false,
+		"ReconnectRequested":    false,
+		"InvalidSession":        false,
+		"Identifying":           false,
+		"Resuming":              false,
+		"ShardReady":            false,
+		"GuildsReady":           false,
+		"RequestRetry":          false,
+		"InvalidRequestWarning": false,
 	}
 	return !options[name]
 }
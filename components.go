@@ -28,11 +28,15 @@ type unmarshalableMessageComponent struct {
 }
 
 func (umc *unmarshalableMessageComponent) UnmarshalJSON(src []byte) error {
+	return umc.unmarshalFrom(jsonSource(src))
+}
+
+func (umc *unmarshalableMessageComponent) unmarshalFrom(decode decodeFunc) error {
 	var v struct {
 		Type ComponentType `json:"type"`
 	}
 
-	if err := Unmarshal(src, &v); err != nil {
+	if err := decode(&v); err != nil {
 		return err
 	}
 
@@ -51,7 +55,7 @@ func (umc *unmarshalableMessageComponent) UnmarshalJSON(src []byte) error {
 		return fmt.Errorf("unknown component type: %d", v.Type)
 	}
 
-	if err := Unmarshal(src, component); err != nil {
+	if err := decode(component); err != nil {
 		return err
 	}
 
@@ -84,11 +88,15 @@ func (r ActionsRow) MarshalJSON() ([]byte, error) {
 }
 
 func (r *ActionsRow) UnmarshalJSON(data []byte) error {
+	return r.unmarshalFrom(jsonSource(data))
+}
+
+func (r *ActionsRow) unmarshalFrom(decode decodeFunc) error {
 	var v struct {
 		RawComponents []unmarshalableMessageComponent `json:"components"`
 	}
 
-	if err := Unmarshal(data, &v); err != nil {
+	if err := decode(&v); err != nil {
 		return err
 	}
 
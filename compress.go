@@ -0,0 +1,185 @@
+package discordgo
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+type GatewayCompression string
+
+const (
+	GatewayCompressionNone       GatewayCompression = ""
+	GatewayCompressionZlibStream GatewayCompression = "zlib-stream"
+	GatewayCompressionZstdStream GatewayCompression = "zstd-stream"
+)
+
+var zlibSuffix = []byte{0x00, 0x00, 0xff, 0xff}
+
+type streamInflater struct {
+	sync.Mutex
+	cond     *sync.Cond
+	input    []byte
+	output   bytes.Buffer
+	checked  int
+	starved  bool
+	closed   bool
+	err      error
+	suffix   []byte
+	complete func([]byte) bool
+}
+
+func newStreamInflater(compression GatewayCompression, encoding GatewayEncoding) (*streamInflater, error) {
+	var newReader func(io.Reader) (io.ReadCloser, error)
+
+	inf := &streamInflater{complete: json.Valid}
+	if encoding == GatewayEncodingETF {
+		inf.complete = etfComplete
+	}
+
+	switch compression {
+	case GatewayCompressionZlibStream:
+		newReader = zlib.NewReader
+		inf.suffix = zlibSuffix
+	case GatewayCompressionZstdStream:
+		if NewZstdReader == nil {
+			return nil, ErrZstdUnavailable
+		}
+		newReader = NewZstdReader
+	default:
+		return nil, ErrUnknownCompression
+	}
+
+	inf.cond = sync.NewCond(inf)
+	go inf.run(newReader)
+
+	return inf, nil
+}
+
+func (inf *streamInflater) run(newReader func(io.Reader) (io.ReadCloser, error)) {
+	r, err := newReader(inflaterSource{inf})
+	if err != nil {
+		inf.fail(err)
+		return
+	}
+	defer r.Close()
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buf)
+
+		inf.Lock()
+		inf.output.Write(buf[:n])
+		if n > 0 {
+			inf.cond.Broadcast()
+		}
+		inf.Unlock()
+
+		if err != nil {
+			inf.fail(err)
+			return
+		}
+	}
+}
+
+func (inf *streamInflater) fail(err error) {
+	inf.Lock()
+	if inf.err == nil {
+		inf.err = err
+	}
+	inf.cond.Broadcast()
+	inf.Unlock()
+}
+
+func (inf *streamInflater) inflate(frame []byte) ([]byte, error) {
+	inf.Lock()
+	defer inf.Unlock()
+
+	if inf.err != nil {
+		return nil, inf.err
+	}
+
+	inf.input = append(inf.input, frame...)
+	inf.starved = false
+	inf.cond.Broadcast()
+	if inf.suffix != nil && !bytes.HasSuffix(frame, inf.suffix) {
+		return nil, nil
+	}
+
+	for !inf.ready() {
+		if inf.closed {
+			return nil, io.ErrUnexpectedEOF
+		}
+		inf.cond.Wait()
+	}
+
+	if inf.err != nil {
+		return nil, inf.err
+	}
+
+	payload := make([]byte, inf.output.Len())
+	copy(payload, inf.output.Bytes())
+	inf.output.Reset()
+	inf.checked = 0
+
+	return payload, nil
+}
+
+func (inf *streamInflater) ready() bool {
+	if inf.err != nil {
+		return true
+	}
+
+	if !inf.starved || inf.output.Len() == inf.checked {
+		return false
+	}
+
+	inf.checked = inf.output.Len()
+	return inf.complete(inf.output.Bytes())
+}
+
+func etfComplete(b []byte) bool {
+	_, err := etfDecode(b)
+	return err == nil
+}
+
+func (inf *streamInflater) Close() {
+	inf.Lock()
+	inf.closed = true
+	inf.cond.Broadcast()
+	inf.Unlock()
+}
+
+type inflaterSource struct {
+	inf *streamInflater
+}
+
+func (src inflaterSource) Read(p []byte) (int, error) {
+	inf := src.inf
+
+	inf.Lock()
+	defer inf.Unlock()
+
+	for len(inf.input) == 0 {
+		if inf.closed {
+			return 0, io.EOF
+		}
+
+		if !inf.starved {
+			inf.starved = true
+			inf.cond.Broadcast()
+		}
+
+		inf.cond.Wait()
+	}
+
+	n := copy(p, inf.input)
+	inf.input = inf.input[n:]
+	if len(inf.input) == 0 {
+		inf.input = nil
+	}
+
+	return n, nil
+}
@@ -0,0 +1,177 @@
+package discordgo
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestStreamInflaterZlib(t *testing.T) {
+	inf, err := newStreamInflater(GatewayCompressionZlibStream, GatewayEncodingJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inf.Close()
+
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+
+	messages := []string{
+		`{"op":10,"d":{"heartbeat_interval":41250}}`,
+		`{"op":0,"s":1,"t":"READY","d":{"session_id":"abc"}}`,
+		`{"op":11}`,
+	}
+
+	for i, msg := range messages {
+		compressed.Reset()
+		if _, err := w.Write([]byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		frame := append([]byte(nil), compressed.Bytes()...)
+		if !bytes.HasSuffix(frame, zlibSuffix) {
+			t.Fatalf("message %d: flushed frame does not end with the zlib suffix", i)
+		}
+
+		if i == 1 {
+			half := len(frame) / 2
+			payload, err := inf.inflate(frame[:half])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if payload != nil {
+				t.Fatalf("message %d: got a payload from a partial frame: %q", i, payload)
+			}
+			frame = frame[half:]
+		}
+
+		payload, err := inf.inflate(frame)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(payload) != msg {
+			t.Fatalf("message %d: got %q, want %q", i, payload, msg)
+		}
+	}
+}
+
+func readAheadReader(src io.Reader) (io.ReadCloser, error) {
+	pr, pw := io.Pipe()
+
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := src.Read(buf)
+			if n > 0 {
+				chunk := append([]byte(nil), buf[:n]...)
+				go func() {
+					time.Sleep(20 * time.Millisecond)
+					half := len(chunk) / 2
+					pw.Write(chunk[:half])
+					time.Sleep(20 * time.Millisecond)
+					pw.Write(chunk[half:])
+				}()
+			}
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+	}()
+
+	return pr, nil
+}
+
+func TestStreamInflaterZstd(t *testing.T) {
+	newZstdReader := NewZstdReader
+	NewZstdReader = readAheadReader
+	defer func() {
+		NewZstdReader = newZstdReader
+	}()
+
+	inf, err := newStreamInflater(GatewayCompressionZstdStream, GatewayEncodingJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inf.Close()
+
+	messages := []string{
+		`{"op":10,"d":{"heartbeat_interval":41250}}`,
+		`{"op":0,"s":1,"t":"READY","d":{"session_id":"abc"}}`,
+	}
+
+	for i, msg := range messages {
+		payload, err := inf.inflate([]byte(msg))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(payload) != msg {
+			t.Fatalf("message %d: got %q, want %q", i, payload, msg)
+		}
+	}
+}
+
+func TestStreamInflaterZstdETF(t *testing.T) {
+	newZstdReader := NewZstdReader
+	NewZstdReader = readAheadReader
+	defer func() {
+		NewZstdReader = newZstdReader
+	}()
+
+	inf, err := newStreamInflater(GatewayCompressionZstdStream, GatewayEncodingETF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inf.Close()
+
+	frame, err := ETFMarshal(map[string]interface{}{"op": 11})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := inf.inflate(frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(payload, frame) {
+		t.Fatalf("got %v, want %v", payload, frame)
+	}
+}
+
+func TestStreamInflaterCorrupt(t *testing.T) {
+	inf, err := newStreamInflater(GatewayCompressionZlibStream, GatewayEncodingJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inf.Close()
+
+	frame := append([]byte("not zlib at all"), zlibSuffix...)
+	if _, err := inf.inflate(frame); err == nil {
+		t.Fatal("expected an error inflating a corrupt frame")
+	}
+
+	if _, err := inf.inflate(frame); err == nil {
+		t.Fatal("expected the inflater to stay failed")
+	}
+}
+
+func TestNewStreamInflaterErrors(t *testing.T) {
+	if _, err := newStreamInflater("gzip", GatewayEncodingJSON); err != ErrUnknownCompression {
+		t.Fatalf("got %v, want %v", err, ErrUnknownCompression)
+	}
+
+	newZstdReader := NewZstdReader
+	NewZstdReader = nil
+	defer func() {
+		NewZstdReader = newZstdReader
+	}()
+
+	if _, err := newStreamInflater(GatewayCompressionZstdStream, GatewayEncodingJSON); err != ErrZstdUnavailable {
+		t.Fatalf("got %v, want %v", err, ErrZstdUnavailable)
+	}
+}
@@ -16,8 +16,10 @@ func New(token string) (*Session, error) {
 	session := &Session{
 		State:                              NewState(),
 		Ratelimiter:                        NewRatelimiter(),
+		GatewayRateLimiter:                 NewGatewayRateLimiter(),
 		StateEnabled:                       true,
 		Compress:                           true,
+		Encoding:                           GatewayEncodingJSON,
 		Token:                              token,
 		Client:                             &http.Client{Timeout: clientTimeout},
 		Dialer:                             websocket.DefaultDialer,
@@ -27,6 +29,8 @@ func New(token string) (*Session, error) {
 		ShardID:                            0,
 		ShardCount:                         1,
 		MaxRestRetries:                     3,
+		RetryPolicy:                        DefaultRetryPolicy,
+		InvalidRequests:                    NewInvalidRequestBreaker(),
 		ShouldReconnectOnError:             true,
 		ShouldReconnectVoiceOnSessionError: true,
 		ShouldRetryOnRateLimit:             true,
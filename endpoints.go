@@ -20,6 +20,7 @@ var (
 	EndpointStickers                      = EndpointAPI + "stickers/"
 	EndpointStageInstances                = EndpointAPI + "stage-instances"
 	EndpointSKUs                          = EndpointAPI + "skus"
+	EndpointSoundboardDefaultSounds       = EndpointAPI + "soundboard-default-sounds"
 	EndpointCDN                           = "https://cdn.discordapp.com/"
 	EndpointCDNAttachments                = EndpointCDN + "attachments/"
 	EndpointCDNAvatars                    = EndpointCDN + "avatars/"
@@ -57,6 +58,7 @@ var (
 	EndpointGuildMemberRole               = func(gID, uID, rID string) string { return EndpointGuilds + gID + "/members/" + uID + "/roles/" + rID }
 	EndpointGuildBans                     = func(gID string) string { return EndpointGuilds + gID + "/bans" }
 	EndpointGuildBan                      = func(gID, uID string) string { return EndpointGuilds + gID + "/bans/" + uID }
+	EndpointGuildBulkBan                  = func(gID string) string { return EndpointGuilds + gID + "/bulk-ban" }
 	EndpointGuildIntegrations             = func(gID string) string { return EndpointGuilds + gID + "/integrations" }
 	EndpointGuildIntegration              = func(gID, iID string) string { return EndpointGuilds + gID + "/integrations/" + iID }
 	EndpointGuildRoles                    = func(gID string) string { return EndpointGuilds + gID + "/roles" }
@@ -64,6 +66,10 @@ var (
 	EndpointGuildInvites                  = func(gID string) string { return EndpointGuilds + gID + "/invites" }
 	EndpointGuildWidget                   = func(gID string) string { return EndpointGuilds + gID + "/widget" }
 	EndpointGuildEmbed                    = EndpointGuildWidget
+	EndpointGuildWidgetJSON               = func(gID string) string { return EndpointGuilds + gID + "/widget.json" }
+	EndpointGuildWidgetImage              = func(gID string) string { return EndpointGuilds + gID + "/widget.png" }
+	EndpointGuildVanityURL                = func(gID string) string { return EndpointGuilds + gID + "/vanity-url" }
+	EndpointGuildWelcomeScreen            = func(gID string) string { return EndpointGuilds + gID + "/welcome-screen" }
 	EndpointGuildPrune                    = func(gID string) string { return EndpointGuilds + gID + "/prune" }
 	EndpointGuildIcon                     = func(gID, hash string) string { return EndpointCDNIcons + gID + "/" + hash + ".png" }
 	EndpointGuildIconAnimated             = func(gID, hash string) string { return EndpointCDNIcons + gID + "/" + hash + ".gif" }
@@ -76,6 +82,8 @@ var (
 	EndpointGuildBannerAnimated           = func(gID, hash string) string { return EndpointCDNBanners + gID + "/" + hash + ".gif" }
 	EndpointGuildStickers                 = func(gID string) string { return EndpointGuilds + gID + "/stickers" }
 	EndpointGuildSticker                  = func(gID, sID string) string { return EndpointGuilds + gID + "/stickers/" + sID }
+	EndpointGuildSoundboardSounds         = func(gID string) string { return EndpointGuilds + gID + "/soundboard-sounds" }
+	EndpointGuildSoundboardSound          = func(gID, sID string) string { return EndpointGuilds + gID + "/soundboard-sounds/" + sID }
 	EndpointStageInstance                 = func(cID string) string { return EndpointStageInstances + "/" + cID }
 	EndpointGuildScheduledEvents          = func(gID string) string { return EndpointGuilds + gID + "/scheduled-events" }
 	EndpointGuildScheduledEvent           = func(gID, eID string) string { return EndpointGuilds + gID + "/scheduled-events/" + eID }
@@ -115,11 +123,13 @@ var (
 	EndpointChannelMessagePin                   = func(cID, mID string) string { return EndpointChannel(cID) + "/pins/" + mID }
 	EndpointChannelMessageCrosspost             = func(cID, mID string) string { return EndpointChannel(cID) + "/messages/" + mID + "/crosspost" }
 	EndpointChannelFollow                       = func(cID string) string { return EndpointChannel(cID) + "/followers" }
+	EndpointChannelSendSoundboardSound          = func(cID string) string { return EndpointChannel(cID) + "/send-soundboard-sound" }
 	EndpointThreadMembers                       = func(tID string) string { return EndpointChannel(tID) + "/thread-members" }
 	EndpointThreadMember                        = func(tID, mID string) string { return EndpointThreadMembers(tID) + "/" + mID }
 	EndpointGroupIcon                           = func(cID, hash string) string { return EndpointCDNChannelIcons + cID + "/" + hash + ".png" }
 	EndpointSticker                             = func(sID string) string { return EndpointStickers + sID }
-	EndpointNitroStickersPacks                  = EndpointAPI + "/sticker-packs"
+	EndpointNitroStickersPacks                  = EndpointAPI + "sticker-packs"
+	EndpointStickerPack                         = func(pID string) string { return EndpointNitroStickersPacks + "/" + pID }
 	EndpointChannelWebhooks                     = func(cID string) string { return EndpointChannel(cID) + "/webhooks" }
 	EndpointWebhook                             = func(wID string) string { return EndpointWebhooks + wID }
 	EndpointWebhookToken                        = func(wID, token string) string { return EndpointWebhooks + wID + "/" + token }
@@ -0,0 +1,1337 @@
+package discordgo
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type GatewayEncoding string
+
+const (
+	GatewayEncodingJSON GatewayEncoding = "json"
+	GatewayEncodingETF  GatewayEncoding = "etf"
+)
+
+const (
+	etfVersion          = 131
+	etfCompressed       = 80
+	etfNewFloat         = 70
+	etfBitBinary        = 77
+	etfSmallInteger     = 97
+	etfInteger          = 98
+	etfFloat            = 99
+	etfAtom             = 100
+	etfSmallTuple       = 104
+	etfLargeTuple       = 105
+	etfNil              = 106
+	etfString           = 107
+	etfList             = 108
+	etfBinary           = 109
+	etfSmallBig         = 110
+	etfLargeBig         = 111
+	etfSmallAtom        = 115
+	etfMap              = 116
+	etfAtomUTF8         = 118
+	etfSmallAtomUTF8    = 119
+	etfMaxNestingDepth  = 10000
+	etfMaxInflatedBytes = 1 << 30
+	etfMaxSafeInteger   = 1<<53 - 1
+)
+
+func ETFUnmarshal(data []byte, v interface{}) error {
+	term, err := etfDecode(data)
+	if err != nil {
+		return err
+	}
+
+	return etfAssign(term, v)
+}
+
+func ETFMarshal(v interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte(etfVersion)
+	if err := etfEncodeValue(buf, reflect.ValueOf(v), 0); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+type decodeFunc func(v interface{}) error
+
+type sourceUnmarshaler interface {
+	unmarshalFrom(decode decodeFunc) error
+}
+
+func jsonSource(data []byte) decodeFunc {
+	return func(v interface{}) error {
+		return Unmarshal(data, v)
+	}
+}
+
+func etfSource(term interface{}) decodeFunc {
+	return func(v interface{}) error {
+		return etfAssign(term, v)
+	}
+}
+
+type rawValue struct {
+	data json.RawMessage
+	term interface{}
+	etf  bool
+}
+
+func (r *rawValue) UnmarshalJSON(data []byte) error {
+	r.data = append(r.data[:0], data...)
+	return nil
+}
+
+func (r *rawValue) unmarshalFrom(decode decodeFunc) error {
+	r.etf = true
+	return decode(&r.term)
+}
+
+func (r rawValue) decode(v interface{}) error {
+	if r.etf {
+		return etfAssign(r.term, v)
+	}
+
+	return Unmarshal(r.data, v)
+}
+
+func etfEvent(data []byte) (*Event, error) {
+	term, err := etfDecode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	m, ok := term.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: payload is not a map", ErrETFMalformed)
+	}
+
+	e := &Event{etf: true, term: m["d"]}
+	a := &etfAssigner{}
+	a.assign(m["op"], reflect.ValueOf(&e.Operation).Elem())
+	a.assign(m["s"], reflect.ValueOf(&e.Sequence).Elem())
+	a.assign(m["t"], reflect.ValueOf(&e.Type).Elem())
+	if a.err != nil {
+		return nil, a.err
+	}
+
+	return e, nil
+}
+
+func etfJSON(term interface{}) (json.RawMessage, error) {
+	return Marshal(etfJSONTerm(term))
+}
+
+func etfJSONTerm(term interface{}) interface{} {
+	switch t := term.(type) {
+	case int64:
+		if t > etfMaxSafeInteger || t < -etfMaxSafeInteger {
+			return strconv.FormatInt(t, 10)
+		}
+	case uint64:
+		if t > etfMaxSafeInteger {
+			return strconv.FormatUint(t, 10)
+		}
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, v := range t {
+			out[i] = etfJSONTerm(v)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			out[k] = etfJSONTerm(v)
+		}
+		return out
+	}
+
+	return term
+}
+
+func etfDecode(data []byte) (interface{}, error) {
+	if len(data) == 0 || data[0] != etfVersion {
+		return nil, fmt.Errorf("%w: missing version header", ErrETFMalformed)
+	}
+
+	d := &etfDecoder{data: data, pos: 1}
+	term, err := d.term(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrETFMalformed, len(d.data)-d.pos)
+	}
+
+	return term, nil
+}
+
+type etfDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *etfDecoder) read(n int) ([]byte, error) {
+	if n < 0 || d.pos+n > len(d.data) {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrETFMalformed)
+	}
+
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *etfDecoder) uint8() (int, error) {
+	b, err := d.read(1)
+	if err != nil {
+		return 0, err
+	}
+	return int(b[0]), nil
+}
+
+func (d *etfDecoder) uint16() (int, error) {
+	b, err := d.read(2)
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint16(b)), nil
+}
+
+func (d *etfDecoder) uint32() (int, error) {
+	b, err := d.read(4)
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint32(b)), nil
+}
+
+func (d *etfDecoder) term(depth int) (interface{}, error) {
+	if depth > etfMaxNestingDepth {
+		return nil, fmt.Errorf("%w: nesting too deep", ErrETFMalformed)
+	}
+
+	tag, err := d.uint8()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tag {
+	case etfSmallInteger:
+		n, err := d.uint8()
+		if err != nil {
+			return nil, err
+		}
+		return int64(n), nil
+
+	case etfInteger:
+		b, err := d.read(4)
+		if err != nil {
+			return nil, err
+		}
+		return int64(int32(binary.BigEndian.Uint32(b))), nil
+
+	case etfNewFloat:
+		b, err := d.read(8)
+		if err != nil {
+			return nil, err
+		}
+		return etfFloatTerm(math.Float64frombits(binary.BigEndian.Uint64(b))), nil
+
+	case etfFloat:
+		b, err := d.read(31)
+		if err != nil {
+			return nil, err
+		}
+		f, err := strconv.ParseFloat(string(bytes.TrimRight(b, "\x00")), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid float, %s", ErrETFMalformed, err)
+		}
+		return etfFloatTerm(f), nil
+
+	case etfAtom, etfAtomUTF8, etfSmallAtom, etfSmallAtomUTF8:
+		name, err := d.atomName(tag)
+		if err != nil {
+			return nil, err
+		}
+
+		switch name {
+		case "nil", "null":
+			return nil, nil
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return name, nil
+
+	case etfSmallTuple, etfLargeTuple:
+		var n int
+		if tag == etfSmallTuple {
+			n, err = d.uint8()
+		} else {
+			n, err = d.uint32()
+		}
+		if err != nil {
+			return nil, err
+		}
+		return d.list(n, depth)
+
+	case etfNil:
+		return []interface{}{}, nil
+
+	case etfString:
+		n, err := d.uint16()
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.read(n)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+
+	case etfList:
+		n, err := d.uint32()
+		if err != nil {
+			return nil, err
+		}
+		list, err := d.list(n, depth)
+		if err != nil {
+			return nil, err
+		}
+
+		tail, err := d.term(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := tail.([]interface{}); !ok || len(t) > 0 {
+			list = append(list, tail)
+		}
+		return list, nil
+
+	case etfBinary:
+		n, err := d.uint32()
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.read(n)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+
+	case etfBitBinary:
+		n, err := d.uint32()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := d.read(1); err != nil {
+			return nil, err
+		}
+		b, err := d.read(n)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+
+	case etfSmallBig, etfLargeBig:
+		var n int
+		if tag == etfSmallBig {
+			n, err = d.uint8()
+		} else {
+			n, err = d.uint32()
+		}
+		if err != nil {
+			return nil, err
+		}
+		return d.bigInt(n)
+
+	case etfMap:
+		n, err := d.uint32()
+		if err != nil {
+			return nil, err
+		}
+		return d.object(n, depth)
+
+	case etfCompressed:
+		size, err := d.uint32()
+		if err != nil {
+			return nil, err
+		}
+		if size > etfMaxInflatedBytes {
+			return nil, fmt.Errorf("%w: compressed term too large", ErrETFMalformed)
+		}
+
+		z, err := zlib.NewReader(bytes.NewReader(d.data[d.pos:]))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrETFMalformed, err)
+		}
+		defer z.Close()
+
+		inflated := make([]byte, size)
+		if _, err := io.ReadFull(z, inflated); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrETFMalformed, err)
+		}
+
+		inner := &etfDecoder{data: inflated}
+		term, err := inner.term(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		d.pos = len(d.data)
+		return term, nil
+	}
+
+	return nil, fmt.Errorf("%w: unsupported tag %d", ErrETFMalformed, tag)
+}
+
+func (d *etfDecoder) atomName(tag int) (string, error) {
+	var n int
+	var err error
+	if tag == etfAtom || tag == etfAtomUTF8 {
+		n, err = d.uint16()
+	} else {
+		n, err = d.uint8()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	b, err := d.read(n)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func (d *etfDecoder) list(n, depth int) ([]interface{}, error) {
+	if n > len(d.data)-d.pos {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrETFMalformed)
+	}
+
+	list := make([]interface{}, n)
+	for i := range list {
+		term, err := d.term(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = term
+	}
+
+	return list, nil
+}
+
+func (d *etfDecoder) object(n, depth int) (map[string]interface{}, error) {
+	if n > len(d.data)-d.pos {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrETFMalformed)
+	}
+
+	m := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		key, err := d.key(depth)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := d.term(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = value
+	}
+
+	return m, nil
+}
+
+func (d *etfDecoder) key(depth int) (string, error) {
+	if d.pos < len(d.data) {
+		switch tag := int(d.data[d.pos]); tag {
+		case etfAtom, etfAtomUTF8, etfSmallAtom, etfSmallAtomUTF8:
+			d.pos++
+			return d.atomName(tag)
+		}
+	}
+
+	term, err := d.term(depth + 1)
+	if err != nil {
+		return "", err
+	}
+
+	switch t := term.(type) {
+	case string:
+		return t, nil
+	case nil:
+		return "null", nil
+	}
+
+	return fmt.Sprint(term), nil
+}
+
+func (d *etfDecoder) bigInt(n int) (interface{}, error) {
+	sign, err := d.uint8()
+	if err != nil {
+		return nil, err
+	}
+
+	digits, err := d.read(n)
+	if err != nil {
+		return nil, err
+	}
+
+	if n <= 8 {
+		var v uint64
+		for i := n - 1; i >= 0; i-- {
+			v = v<<8 | uint64(digits[i])
+		}
+
+		switch {
+		case sign == 0 && v <= math.MaxInt64:
+			return int64(v), nil
+		case sign == 0:
+			return v, nil
+		case v <= math.MaxInt64:
+			return -int64(v), nil
+		case v == math.MaxInt64+1:
+			return int64(math.MinInt64), nil
+		}
+	}
+
+	be := make([]byte, n)
+	for i := range digits {
+		be[n-1-i] = digits[i]
+	}
+
+	v := new(big.Int).SetBytes(be)
+	if sign != 0 {
+		v.Neg(v)
+	}
+
+	return v.String(), nil
+}
+
+func etfFloatTerm(f float64) interface{} {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil
+	}
+
+	return f
+}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+func etfAssign(term interface{}, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(v)}
+	}
+
+	a := &etfAssigner{}
+	a.assign(term, rv.Elem())
+	return a.err
+}
+
+type etfAssigner struct {
+	err error
+}
+
+func (a *etfAssigner) fail(term interface{}, t reflect.Type) {
+	if a.err == nil {
+		a.err = &json.UnmarshalTypeError{Value: fmt.Sprintf("%T", term), Type: t}
+	}
+}
+
+func (a *etfAssigner) failErr(err error) {
+	if a.err == nil && err != nil {
+		a.err = err
+	}
+}
+
+func (a *etfAssigner) assign(term interface{}, rv reflect.Value) {
+	if term == nil {
+		switch rv.Kind() {
+		case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+			rv.Set(reflect.Zero(rv.Type()))
+		}
+		return
+	}
+
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.CanAddr() {
+		p := rv.Addr().Interface()
+		if u, ok := p.(sourceUnmarshaler); ok {
+			a.failErr(u.unmarshalFrom(etfSource(term)))
+			return
+		}
+		if s, ok := term.(string); ok && rv.Addr().Type().Implements(textUnmarshalerType) {
+			a.failErr(p.(encoding.TextUnmarshaler).UnmarshalText([]byte(s)))
+			return
+		}
+		if u, ok := p.(json.Unmarshaler); ok {
+			b, err := Marshal(term)
+			if err != nil {
+				a.failErr(err)
+				return
+			}
+			a.failErr(u.UnmarshalJSON(b))
+			return
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.NumMethod() != 0 {
+			a.fail(term, rv.Type())
+			return
+		}
+		rv.Set(reflect.ValueOf(term))
+
+	case reflect.Bool:
+		switch t := term.(type) {
+		case bool:
+			rv.SetBool(t)
+		case string:
+			b, err := strconv.ParseBool(t)
+			if err != nil {
+				a.fail(term, rv.Type())
+				return
+			}
+			rv.SetBool(b)
+		default:
+			a.fail(term, rv.Type())
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := etfInt(term)
+		if !ok || rv.OverflowInt(n) {
+			a.fail(term, rv.Type())
+			return
+		}
+		rv.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, ok := etfUint(term)
+		if !ok || rv.OverflowUint(n) {
+			a.fail(term, rv.Type())
+			return
+		}
+		rv.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		f, ok := etfFloatValue(term)
+		if !ok || rv.OverflowFloat(f) {
+			a.fail(term, rv.Type())
+			return
+		}
+		rv.SetFloat(f)
+
+	case reflect.String:
+		switch t := term.(type) {
+		case string:
+			rv.SetString(t)
+		case int64:
+			rv.SetString(strconv.FormatInt(t, 10))
+		case uint64:
+			rv.SetString(strconv.FormatUint(t, 10))
+		case float64:
+			rv.SetString(strconv.FormatFloat(t, 'g', -1, 64))
+		default:
+			a.fail(term, rv.Type())
+		}
+
+	case reflect.Slice:
+		if s, ok := term.(string); ok && rv.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				a.failErr(err)
+				return
+			}
+			rv.SetBytes(b)
+			return
+		}
+
+		list, ok := term.([]interface{})
+		if !ok {
+			a.fail(term, rv.Type())
+			return
+		}
+
+		slice := reflect.MakeSlice(rv.Type(), len(list), len(list))
+		for i, e := range list {
+			a.assign(e, slice.Index(i))
+		}
+		rv.Set(slice)
+
+	case reflect.Array:
+		list, ok := term.([]interface{})
+		if !ok {
+			a.fail(term, rv.Type())
+			return
+		}
+
+		for i := 0; i < rv.Len(); i++ {
+			if i < len(list) {
+				a.assign(list[i], rv.Index(i))
+			} else {
+				rv.Index(i).Set(reflect.Zero(rv.Type().Elem()))
+			}
+		}
+
+	case reflect.Map:
+		m, ok := term.(map[string]interface{})
+		if !ok {
+			a.fail(term, rv.Type())
+			return
+		}
+
+		t := rv.Type()
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMapWithSize(t, len(m)))
+		}
+
+		for k, e := range m {
+			key := reflect.New(t.Key()).Elem()
+			switch t.Key().Kind() {
+			case reflect.String:
+				key.SetString(k)
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				a.assign(k, key)
+			default:
+				a.fail(term, t)
+				return
+			}
+
+			value := reflect.New(t.Elem()).Elem()
+			a.assign(e, value)
+			rv.SetMapIndex(key, value)
+		}
+
+	case reflect.Struct:
+		m, ok := term.(map[string]interface{})
+		if !ok {
+			a.fail(term, rv.Type())
+			return
+		}
+
+		fields := etfCachedFields(rv.Type())
+		for k, e := range m {
+			f := fields.lookup(k)
+			if f == nil {
+				continue
+			}
+
+			fv, ok := etfFieldByIndex(rv, f.index, true)
+			if !ok {
+				continue
+			}
+			a.assign(e, fv)
+		}
+
+	default:
+		a.fail(term, rv.Type())
+	}
+}
+
+func etfInt(term interface{}) (int64, bool) {
+	switch t := term.(type) {
+	case int64:
+		return t, true
+	case uint64:
+		if t <= math.MaxInt64 {
+			return int64(t), true
+		}
+	case float64:
+		if t == math.Trunc(t) && t >= math.MinInt64 && t <= math.MaxInt64 {
+			return int64(t), true
+		}
+	case string:
+		n, err := strconv.ParseInt(t, 10, 64)
+		return n, err == nil
+	}
+
+	return 0, false
+}
+
+func etfUint(term interface{}) (uint64, bool) {
+	switch t := term.(type) {
+	case int64:
+		if t >= 0 {
+			return uint64(t), true
+		}
+	case uint64:
+		return t, true
+	case float64:
+		if t == math.Trunc(t) && t >= 0 && t <= math.MaxUint64 {
+			return uint64(t), true
+		}
+	case string:
+		n, err := strconv.ParseUint(t, 10, 64)
+		return n, err == nil
+	}
+
+	return 0, false
+}
+
+func etfFloatValue(term interface{}) (float64, bool) {
+	switch t := term.(type) {
+	case int64:
+		return float64(t), true
+	case uint64:
+		return float64(t), true
+	case float64:
+		return t, true
+	case string:
+		f, err := strconv.ParseFloat(t, 64)
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+type etfField struct {
+	name      string
+	index     []int
+	tagged    bool
+	omitEmpty bool
+	quoted    bool
+}
+
+type etfFieldList struct {
+	fields []etfField
+	byName map[string]*etfField
+}
+
+func (l *etfFieldList) lookup(name string) *etfField {
+	if f, ok := l.byName[name]; ok {
+		return f
+	}
+
+	for i := range l.fields {
+		if strings.EqualFold(l.fields[i].name, name) {
+			return &l.fields[i]
+		}
+	}
+
+	return nil
+}
+
+var etfFieldCache sync.Map
+
+func etfCachedFields(t reflect.Type) *etfFieldList {
+	if l, ok := etfFieldCache.Load(t); ok {
+		return l.(*etfFieldList)
+	}
+
+	l, _ := etfFieldCache.LoadOrStore(t, etfTypeFields(t))
+	return l.(*etfFieldList)
+}
+
+func etfTypeFields(t reflect.Type) *etfFieldList {
+	type candidate struct {
+		etfField
+		depth int
+	}
+
+	var candidates []candidate
+	visited := map[reflect.Type]bool{}
+
+	type level struct {
+		t     reflect.Type
+		index []int
+	}
+	current := []level{{t: t}}
+
+	for depth := 0; len(current) > 0; depth++ {
+		var next []level
+		for _, lv := range current {
+			if visited[lv.t] {
+				continue
+			}
+			visited[lv.t] = true
+
+			for i := 0; i < lv.t.NumField(); i++ {
+				sf := lv.t.Field(i)
+
+				ft := sf.Type
+				if ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+
+				if sf.PkgPath != "" && !(sf.Anonymous && ft.Kind() == reflect.Struct) {
+					continue
+				}
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+
+				name, opts := tag, ""
+				if i := strings.Index(tag, ","); i >= 0 {
+					name, opts = tag[:i], tag[i+1:]
+				}
+
+				index := make([]int, len(lv.index)+1)
+				copy(index, lv.index)
+				index[len(lv.index)] = i
+
+				if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+					next = append(next, level{t: ft, index: index})
+					continue
+				}
+				if sf.PkgPath != "" {
+					continue
+				}
+
+				f := etfField{
+					name:   name,
+					index:  index,
+					tagged: name != "",
+				}
+				if f.name == "" {
+					f.name = sf.Name
+				}
+				for _, opt := range strings.Split(opts, ",") {
+					switch opt {
+					case "omitempty":
+						f.omitEmpty = true
+					case "string":
+						switch ft.Kind() {
+						case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+							reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+							reflect.Float32, reflect.Float64, reflect.String:
+							f.quoted = true
+						}
+					}
+				}
+
+				candidates = append(candidates, candidate{f, depth})
+			}
+		}
+		current = next
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].name != candidates[j].name {
+			return candidates[i].name < candidates[j].name
+		}
+		if candidates[i].depth != candidates[j].depth {
+			return candidates[i].depth < candidates[j].depth
+		}
+		return candidates[i].tagged && !candidates[j].tagged
+	})
+
+	l := &etfFieldList{byName: make(map[string]*etfField)}
+	for i := 0; i < len(candidates); {
+		j := i + 1
+		for j < len(candidates) && candidates[j].name == candidates[i].name {
+			j++
+		}
+
+		group := candidates[i:j]
+		dominant := len(group) == 1 ||
+			group[0].depth < group[1].depth ||
+			group[0].tagged && !group[1].tagged
+		if dominant {
+			l.fields = append(l.fields, group[0].etfField)
+		}
+		i = j
+	}
+
+	sort.Slice(l.fields, func(i, j int) bool {
+		return lessIndex(l.fields[i].index, l.fields[j].index)
+	})
+	for i := range l.fields {
+		l.byName[l.fields[i].name] = &l.fields[i]
+	}
+
+	return l
+}
+
+func lessIndex(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+
+	return len(a) < len(b)
+}
+
+func etfFieldByIndex(rv reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				if !alloc || !rv.CanSet() {
+					return reflect.Value{}, false
+				}
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+
+	return rv, true
+}
+
+func etfEncodeValue(buf *bytes.Buffer, rv reflect.Value, depth int) error {
+	if depth > etfMaxNestingDepth {
+		return fmt.Errorf("%w: nesting too deep", ErrETFMalformed)
+	}
+
+	if !rv.IsValid() {
+		etfEncodeAtom(buf, "nil")
+		return nil
+	}
+
+	if (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
+		etfEncodeAtom(buf, "nil")
+		return nil
+	}
+
+	t := rv.Type()
+	if t.Implements(textMarshalerType) {
+		text, err := rv.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+		etfEncodeBinary(buf, string(text))
+		return nil
+	}
+
+	if t.Implements(jsonMarshalerType) {
+		raw, err := rv.Interface().(json.Marshaler).MarshalJSON()
+		if err != nil {
+			return err
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+
+		var term interface{}
+		if err := decoder.Decode(&term); err != nil {
+			return err
+		}
+
+		return etfEncode(buf, term)
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return etfEncodeValue(buf, rv.Elem(), depth+1)
+
+	case reflect.Bool:
+		etfEncodeBool(buf, rv.Bool())
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		etfEncodeInt(buf, rv.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := rv.Uint(); u > math.MaxInt64 {
+			etfEncodeUint(buf, u, false)
+		} else {
+			etfEncodeInt(buf, int64(u))
+		}
+
+	case reflect.Float32, reflect.Float64:
+		buf.WriteByte(etfNewFloat)
+		binary.Write(buf, binary.BigEndian, math.Float64bits(rv.Float()))
+
+	case reflect.String:
+		etfEncodeBinary(buf, rv.String())
+
+	case reflect.Slice:
+		if rv.IsNil() {
+			etfEncodeAtom(buf, "nil")
+			return nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			etfEncodeBinary(buf, base64.StdEncoding.EncodeToString(rv.Bytes()))
+			return nil
+		}
+		return etfEncodeList(buf, rv, depth)
+
+	case reflect.Array:
+		return etfEncodeList(buf, rv, depth)
+
+	case reflect.Map:
+		if rv.IsNil() {
+			etfEncodeAtom(buf, "nil")
+			return nil
+		}
+
+		keys := make([]string, 0, rv.Len())
+		values := make(map[string]reflect.Value, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			k := iter.Key()
+			var key string
+			switch k.Kind() {
+			case reflect.String:
+				key = k.String()
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				key = strconv.FormatInt(k.Int(), 10)
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				key = strconv.FormatUint(k.Uint(), 10)
+			default:
+				return fmt.Errorf("%w: cannot encode map key %s", ErrETFMalformed, k.Type())
+			}
+			keys = append(keys, key)
+			values[key] = iter.Value()
+		}
+		sort.Strings(keys)
+
+		buf.WriteByte(etfMap)
+		binary.Write(buf, binary.BigEndian, uint32(len(keys)))
+		for _, k := range keys {
+			etfEncodeBinary(buf, k)
+			if err := etfEncodeValue(buf, values[k], depth+1); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Struct:
+		fields := etfCachedFields(t)
+
+		type pair struct {
+			field *etfField
+			value reflect.Value
+		}
+		pairs := make([]pair, 0, len(fields.fields))
+		for i := range fields.fields {
+			f := &fields.fields[i]
+			fv, ok := etfFieldByIndex(rv, f.index, false)
+			if !ok || f.omitEmpty && etfEmptyValue(fv) {
+				continue
+			}
+			pairs = append(pairs, pair{f, fv})
+		}
+
+		buf.WriteByte(etfMap)
+		binary.Write(buf, binary.BigEndian, uint32(len(pairs)))
+		for _, p := range pairs {
+			etfEncodeBinary(buf, p.field.name)
+
+			if p.field.quoted {
+				v := p.value
+				for v.Kind() == reflect.Ptr && !v.IsNil() {
+					v = v.Elem()
+				}
+				if v.Kind() != reflect.Ptr {
+					etfEncodeBinary(buf, etfQuote(v))
+					continue
+				}
+			}
+
+			if err := etfEncodeValue(buf, p.value, depth+1); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fmt.Errorf("%w: cannot encode %s", ErrETFMalformed, t)
+	}
+
+	return nil
+}
+
+func etfEncodeList(buf *bytes.Buffer, rv reflect.Value, depth int) error {
+	if rv.Len() == 0 {
+		buf.WriteByte(etfNil)
+		return nil
+	}
+
+	buf.WriteByte(etfList)
+	binary.Write(buf, binary.BigEndian, uint32(rv.Len()))
+	for i := 0; i < rv.Len(); i++ {
+		if err := etfEncodeValue(buf, rv.Index(i), depth+1); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(etfNil)
+
+	return nil
+}
+
+func etfQuote(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	}
+
+	return v.String()
+}
+
+func etfEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+
+	return false
+}
+
+func etfEncode(buf *bytes.Buffer, v interface{}) error {
+	switch t := v.(type) {
+	case nil:
+		etfEncodeAtom(buf, "nil")
+
+	case bool:
+		etfEncodeBool(buf, t)
+
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			etfEncodeInt(buf, i)
+			return nil
+		}
+
+		if u, err := strconv.ParseUint(string(t), 10, 64); err == nil {
+			etfEncodeUint(buf, u, false)
+			return nil
+		}
+
+		f, err := t.Float64()
+		if err != nil {
+			return err
+		}
+		buf.WriteByte(etfNewFloat)
+		binary.Write(buf, binary.BigEndian, math.Float64bits(f))
+
+	case string:
+		etfEncodeBinary(buf, t)
+
+	case []interface{}:
+		if len(t) == 0 {
+			buf.WriteByte(etfNil)
+			return nil
+		}
+
+		buf.WriteByte(etfList)
+		binary.Write(buf, binary.BigEndian, uint32(len(t)))
+		for _, e := range t {
+			if err := etfEncode(buf, e); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(etfNil)
+
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		buf.WriteByte(etfMap)
+		binary.Write(buf, binary.BigEndian, uint32(len(t)))
+		for _, k := range keys {
+			etfEncodeBinary(buf, k)
+			if err := etfEncode(buf, t[k]); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fmt.Errorf("%w: cannot encode %T", ErrETFMalformed, v)
+	}
+
+	return nil
+}
+
+func etfEncodeAtom(buf *bytes.Buffer, name string) {
+	buf.WriteByte(etfSmallAtomUTF8)
+	buf.WriteByte(byte(len(name)))
+	buf.WriteString(name)
+}
+
+func etfEncodeBool(buf *bytes.Buffer, b bool) {
+	if b {
+		etfEncodeAtom(buf, "true")
+	} else {
+		etfEncodeAtom(buf, "false")
+	}
+}
+
+func etfEncodeBinary(buf *bytes.Buffer, s string) {
+	buf.WriteByte(etfBinary)
+	binary.Write(buf, binary.BigEndian, uint32(len(s)))
+	buf.WriteString(s)
+}
+
+func etfEncodeInt(buf *bytes.Buffer, i int64) {
+	switch {
+	case i >= 0 && i <= math.MaxUint8:
+		buf.WriteByte(etfSmallInteger)
+		buf.WriteByte(byte(i))
+	case i >= math.MinInt32 && i <= math.MaxInt32:
+		buf.WriteByte(etfInteger)
+		binary.Write(buf, binary.BigEndian, int32(i))
+	case i < 0:
+		etfEncodeUint(buf, uint64(-(i+1))+1, true)
+	default:
+		etfEncodeUint(buf, uint64(i), false)
+	}
+}
+
+func etfEncodeUint(buf *bytes.Buffer, u uint64, negative bool) {
+	digits := make([]byte, 0, 8)
+	for u > 0 {
+		digits = append(digits, byte(u))
+		u >>= 8
+	}
+
+	buf.WriteByte(etfSmallBig)
+	buf.WriteByte(byte(len(digits)))
+	if negative {
+		buf.WriteByte(1)
+	} else {
+		buf.WriteByte(0)
+	}
+	buf.Write(digits)
+}
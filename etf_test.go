@@ -0,0 +1,185 @@
+package discordgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func jsonToETF(t *testing.T, raw string) []byte {
+	t.Helper()
+
+	dec := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteByte(etfVersion)
+	if err := etfEncode(buf, v); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestETFUnmarshalMatchesJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		new  func() interface{}
+	}{
+		{
+			name: "message with components",
+			raw:  `{"id":"1","channel_id":"2","content":"hi","timestamp":"2024-01-02T03:04:05.123+00:00","edited_timestamp":null,"author":{"id":"3","username":"u"},"components":[{"type":1,"components":[{"type":2,"label":"b","style":1,"custom_id":"x"}]}],"embeds":[],"flags":4}`,
+			new:  func() interface{} { return &MessageCreate{} },
+		},
+		{
+			name: "modal submit interaction",
+			raw:  `{"id":"9","type":5,"data":{"custom_id":"m","components":[{"type":1,"components":[{"type":4,"custom_id":"t","value":"v"}]}]},"app_permissions":"1024"}`,
+			new:  func() interface{} { return &InteractionCreate{} },
+		},
+		{
+			name: "application command interaction",
+			raw:  `{"id":"9","type":2,"data":{"id":"5","name":"cmd","options":[{"name":"a","type":3,"value":"x"}]}}`,
+			new:  func() interface{} { return &InteractionCreate{} },
+		},
+		{
+			name: "presence with activities",
+			raw:  `{"user":{"id":"1"},"status":"online","activities":[{"name":"g","type":0,"created_at":1700000000000,"application_id":"123456789012345678","timestamps":{"start":1700000000000}}]}`,
+			new:  func() interface{} { return &PresenceUpdate{} },
+		},
+		{
+			name: "guild with roles",
+			raw:  `{"id":"1","name":"g","roles":[{"id":"2","name":"r","permissions":"8"}],"joined_at":"2024-01-02T03:04:05Z","member_count":5}`,
+			new:  func() interface{} { return &GuildCreate{} },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.new()
+			if err := Unmarshal([]byte(tt.raw), want); err != nil {
+				t.Fatal(err)
+			}
+
+			got := tt.new()
+			if err := ETFUnmarshal(jsonToETF(t, tt.raw), got); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(want, got) {
+				wb, _ := json.Marshal(want)
+				gb, _ := json.Marshal(got)
+				t.Fatalf("etf decode differs from json\njson: %s\netf:  %s", wb, gb)
+			}
+		})
+	}
+}
+
+func TestETFMarshalMatchesJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+	}{
+		{"identify", identifyOp{2, Identify{Token: "t", Intents: 513, Properties: IdentifyProperties{OS: "linux"}}}},
+		{"status", UpdateStatusData{Status: "online", Activities: []*Activity{{Name: "x", Type: ActivityTypeGame}}}},
+		{"heartbeat", heartbeatOp{1, 1 << 40}},
+		{"request guild members", requestGuildMembersOp{8, requestGuildMembersData{GuildIDs: []string{"1"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ETFMarshal(tt.v)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			term, err := etfDecode(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _ := json.Marshal(term)
+
+			jb, _ := json.Marshal(tt.v)
+			var generic interface{}
+			if err := json.Unmarshal(jb, &generic); err != nil {
+				t.Fatal(err)
+			}
+			want, _ := json.Marshal(generic)
+
+			if string(got) != string(want) {
+				t.Fatalf("etf encode differs from json\njson: %s\netf:  %s", want, got)
+			}
+		})
+	}
+}
+
+func TestETFEvent(t *testing.T) {
+	frame := jsonToETF(t, `{"op":0,"s":42,"t":"MESSAGE_CREATE","d":{"id":"1","channel_id":"2","content":"hi"}}`)
+
+	e, err := etfEvent(frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if e.Operation != 0 || e.Sequence != 42 || e.Type != "MESSAGE_CREATE" {
+		t.Fatalf("unexpected event header: op %d, seq %d, type %q", e.Operation, e.Sequence, e.Type)
+	}
+
+	var m MessageCreate
+	if err := e.unmarshal(&m); err != nil {
+		t.Fatal(err)
+	}
+
+	if m.Message == nil || m.ID != "1" || m.ChannelID != "2" || m.Content != "hi" {
+		t.Fatalf("unexpected message: %+v", m.Message)
+	}
+}
+
+func TestETFMalformed(t *testing.T) {
+	for _, data := range [][]byte{nil, {0}, {etfVersion}, {etfVersion, 116, 0, 0, 0, 1}} {
+		if _, err := etfDecode(data); err == nil {
+			t.Fatalf("expected an error decoding %v", data)
+		}
+	}
+}
+
+func TestETFJSONStringifiesSnowflakes(t *testing.T) {
+	data, err := ETFMarshal(map[string]interface{}{
+		"id":         int64(1234567890123456789),
+		"channel_id": int64(987654321098765432),
+		"content":    "hi",
+		"flags":      int64(4),
+		"mentions":   []interface{}{map[string]interface{}{"id": int64(112233445566778899)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	term, err := etfDecode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := etfJSON(term)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var m Message
+	if err := Unmarshal(raw, &m); err != nil {
+		t.Fatalf("converted payload does not decode as JSON: %v\n%s", err, raw)
+	}
+
+	if m.ID != "1234567890123456789" || m.ChannelID != "987654321098765432" || m.Flags != 4 {
+		t.Fatalf("unexpected message %+v from %s", m, raw)
+	}
+	if len(m.Mentions) != 1 || m.Mentions[0].ID != "112233445566778899" {
+		t.Fatalf("unexpected mentions from %s", raw)
+	}
+}
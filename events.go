@@ -1,6 +1,9 @@
 package discordgo
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Connect struct{}
 
@@ -11,22 +14,97 @@ type RateLimit struct {
 	URL string
 }
 
+type GatewayFatalClose struct {
+	Code GatewayCloseCode
+	Text string
+}
+
+type ReconnectAttempt struct {
+	Attempt int
+	Delay   time.Duration
+	GuildID string
+	Err     error
+}
+
+type HeartbeatAckMissed struct {
+	LastHeartbeatAck time.Time
+	Elapsed          time.Duration
+}
+
+type ReconnectRequested struct{}
+
+type InvalidSession struct {
+	Resumable bool
+}
+
+type Identifying struct {
+	ShardID    int
+	ShardCount int
+}
+
+type Resuming struct {
+	SessionID string
+	Sequence  int64
+}
+
+type ShardReady struct {
+	ShardID    int
+	ShardCount int
+	Resumed    bool
+}
+
+type GuildsReady struct {
+	TimedOut bool
+	Missing  []string
+}
+
+type InvalidRequestWarning struct {
+	Count   int
+	TripAt  int
+	Window  time.Duration
+	Tripped bool
+}
+
+type RequestRetry struct {
+	Method     string
+	URL        string
+	Attempt    int
+	Delay      time.Duration
+	StatusCode int
+	Err        error
+}
+
 type Event struct {
-	Operation int             `json:"op"`
-	Sequence  int64           `json:"s"`
-	Type      string          `json:"t"`
-	RawData   json.RawMessage `json:"d"`
-	Struct    interface{}     `json:"-"`
+	Operation int    `json:"op"`
+	Sequence  int64  `json:"s"`
+	Type      string `json:"t"`
+	// RawData is the payload as JSON. Under GatewayEncodingETF it is converted
+	// from the ETF payload, with snowflakes written as strings like the JSON
+	// gateway sends them, and it is only filled in when a *Event handler or a
+	// Recorder is registered or LogLevel is LogDebug.
+	RawData json.RawMessage `json:"d"`
+	Struct  interface{}     `json:"-"`
+	term    interface{}
+	etf     bool
+}
+
+func (e *Event) unmarshal(v interface{}) error {
+	if e.etf {
+		return etfAssign(e.term, v)
+	}
+
+	return Unmarshal(e.RawData, v)
 }
 
 type Ready struct {
-	Version         int          `json:"v"`
-	SessionID       string       `json:"session_id"`
-	User            *User        `json:"user"`
-	Shard           *[2]int      `json:"shard"`
-	Application     *Application `json:"application"`
-	Guilds          []*Guild     `json:"guilds"`
-	PrivateChannels []*Channel   `json:"private_channels"`
+	Version          int          `json:"v"`
+	SessionID        string       `json:"session_id"`
+	ResumeGatewayURL string       `json:"resume_gateway_url"`
+	User             *User        `json:"user"`
+	Shard            *[2]int      `json:"shard"`
+	Application      *Application `json:"application"`
+	Guilds           []*Guild     `json:"guilds"`
+	PrivateChannels  []*Channel   `json:"private_channels"`
 }
 
 type ChannelCreate struct {
@@ -84,6 +162,7 @@ type ThreadMembersUpdate struct {
 
 type GuildCreate struct {
 	*Guild
+	Origin GuildCreateOrigin `json:"-"`
 }
 
 type GuildUpdate struct {
@@ -136,6 +215,29 @@ type GuildEmojisUpdate struct {
 	Emojis  []*Emoji `json:"emojis"`
 }
 
+type GuildSoundboardSoundCreate struct {
+	*SoundboardSound
+}
+
+type GuildSoundboardSoundUpdate struct {
+	*SoundboardSound
+}
+
+type GuildSoundboardSoundDelete struct {
+	SoundID string `json:"sound_id"`
+	GuildID string `json:"guild_id"`
+}
+
+type GuildSoundboardSoundsUpdate struct {
+	GuildID          string             `json:"guild_id"`
+	SoundboardSounds []*SoundboardSound `json:"soundboard_sounds"`
+}
+
+type SoundboardSounds struct {
+	GuildID          string             `json:"guild_id"`
+	SoundboardSounds []*SoundboardSound `json:"soundboard_sounds"`
+}
+
 type GuildMembersChunk struct {
 	GuildID    string      `json:"guild_id"`
 	Members    []*Member   `json:"members"`
@@ -210,6 +312,10 @@ func (m *MessageCreate) UnmarshalJSON(b []byte) error {
 	return Unmarshal(b, &m.Message)
 }
 
+func (m *MessageCreate) unmarshalFrom(decode decodeFunc) error {
+	return decode(&m.Message)
+}
+
 type MessageUpdate struct {
 	*Message
 	BeforeUpdate *Message `json:"-"`
@@ -219,6 +325,10 @@ func (m *MessageUpdate) UnmarshalJSON(b []byte) error {
 	return Unmarshal(b, &m.Message)
 }
 
+func (m *MessageUpdate) unmarshalFrom(decode decodeFunc) error {
+	return decode(&m.Message)
+}
+
 type MessageDelete struct {
 	*Message
 	BeforeDelete *Message `json:"-"`
@@ -228,6 +338,10 @@ func (m *MessageDelete) UnmarshalJSON(b []byte) error {
 	return Unmarshal(b, &m.Message)
 }
 
+func (m *MessageDelete) unmarshalFrom(decode decodeFunc) error {
+	return decode(&m.Message)
+}
+
 type MessageReactionAdd struct {
 	*MessageReaction
 	Member *Member `json:"member,omitempty"`
@@ -293,6 +407,10 @@ func (i *InteractionCreate) UnmarshalJSON(b []byte) error {
 	return Unmarshal(b, &i.Interaction)
 }
 
+func (i *InteractionCreate) unmarshalFrom(decode decodeFunc) error {
+	return decode(&i.Interaction)
+}
+
 type InviteCreate struct {
 	*Invite
 	ChannelID string `json:"channel_id"`
@@ -0,0 +1,248 @@
+package discordgo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type fakeGateway struct {
+	sync.Mutex
+	AutoHeartbeatAck bool
+	Encoding         GatewayEncoding
+	URLs             []string
+	conns            chan *fakeGatewayConn
+}
+
+func newfakeGateway() *fakeGateway {
+	return &fakeGateway{
+		AutoHeartbeatAck: true,
+		conns:            make(chan *fakeGatewayConn, 16),
+	}
+}
+
+func (g *fakeGateway) DialGateway(ctx context.Context, url string, header http.Header) (GatewayConn, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	g.Lock()
+	g.URLs = append(g.URLs, url)
+	c := &fakeGatewayConn{
+		autoAck:  g.AutoHeartbeatAck,
+		encoding: g.Encoding,
+		toClient: make(chan fakeFrame, 64),
+		received: make(chan struct{}, 1),
+		closed:   make(chan struct{}),
+	}
+	g.Unlock()
+
+	select {
+	case g.conns <- c:
+		return c, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (g *fakeGateway) Accept(ctx context.Context) (*fakeGatewayConn, error) {
+	select {
+	case c := <-g.conns:
+		return c, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type fakeFrame struct {
+	messageType int
+	data        []byte
+	err         error
+}
+
+type fakeGatewayConn struct {
+	sync.Mutex
+	autoAck    bool
+	encoding   GatewayEncoding
+	sequence   int64
+	toClient   chan fakeFrame
+	fromClient []*Event
+	received   chan struct{}
+	closed     chan struct{}
+	closeOnce  sync.Once
+}
+
+func (c *fakeGatewayConn) ReadMessage() (int, []byte, error) {
+	select {
+	case <-c.closed:
+		return 0, nil, io.ErrClosedPipe
+	default:
+	}
+
+	select {
+	case f := <-c.toClient:
+		if f.err != nil {
+			c.Close()
+			return 0, nil, f.err
+		}
+		return f.messageType, f.data, nil
+	case <-c.closed:
+		return 0, nil, io.ErrClosedPipe
+	}
+}
+
+func (c *fakeGatewayConn) WriteMessage(messageType int, data []byte) error {
+	select {
+	case <-c.closed:
+		return io.ErrClosedPipe
+	default:
+	}
+
+	if messageType == websocket.CloseMessage {
+		return nil
+	}
+
+	var e *Event
+	var err error
+	if messageType == websocket.BinaryMessage {
+		e, err = etfEvent(data)
+	} else {
+		err = Unmarshal(data, &e)
+	}
+	if err != nil {
+		return err
+	}
+
+	if e.Operation == 1 && c.autoAck {
+		return c.Send(11, "", nil)
+	}
+
+	c.Lock()
+	c.fromClient = append(c.fromClient, e)
+	c.Unlock()
+
+	select {
+	case c.received <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (c *fakeGatewayConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+	return nil
+}
+
+func (c *fakeGatewayConn) push(f fakeFrame) error {
+	select {
+	case c.toClient <- f:
+		return nil
+	case <-c.closed:
+		return io.ErrClosedPipe
+	}
+}
+
+func (c *fakeGatewayConn) Send(op int, t string, d interface{}) error {
+	if c.encoding == GatewayEncodingETF {
+		return c.sendETF(op, t, d)
+	}
+
+	raw, err := Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	e := &Event{
+		Operation: op,
+		Type:      t,
+		RawData:   raw,
+	}
+
+	c.Lock()
+	if op == 0 {
+		c.sequence++
+		e.Sequence = c.sequence
+	}
+	b, err := Marshal(e)
+	c.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return c.push(fakeFrame{messageType: websocket.TextMessage, data: b})
+}
+
+func (c *fakeGatewayConn) sendETF(op int, t string, d interface{}) error {
+	payload := map[string]interface{}{"op": op, "d": d, "s": nil, "t": nil}
+
+	c.Lock()
+	if op == 0 {
+		c.sequence++
+		payload["s"] = c.sequence
+		payload["t"] = t
+	}
+	b, err := ETFMarshal(payload)
+	c.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return c.push(fakeFrame{messageType: websocket.BinaryMessage, data: b})
+}
+
+func (c *fakeGatewayConn) Hello(heartbeatInterval time.Duration) error {
+	return c.Send(10, "", map[string]int64{
+		"heartbeat_interval": int64(heartbeatInterval / time.Millisecond),
+	})
+}
+
+func (c *fakeGatewayConn) Dispatch(t string, d interface{}) error {
+	return c.Send(0, t, d)
+}
+
+func (c *fakeGatewayConn) Ready(r *Ready) error {
+	return c.Dispatch("READY", r)
+}
+
+func (c *fakeGatewayConn) Resumed() error {
+	return c.Dispatch("RESUMED", struct{}{})
+}
+
+func (c *fakeGatewayConn) Reconnect() error {
+	return c.Send(7, "", nil)
+}
+
+func (c *fakeGatewayConn) InvalidSession(resumable bool) error {
+	return c.Send(9, "", resumable)
+}
+
+func (c *fakeGatewayConn) CloseWithCode(code int, text string) error {
+	return c.push(fakeFrame{err: &websocket.CloseError{Code: code, Text: text}})
+}
+
+func (c *fakeGatewayConn) Next(ctx context.Context) (*Event, error) {
+	for {
+		c.Lock()
+		if len(c.fromClient) > 0 {
+			e := c.fromClient[0]
+			c.fromClient = c.fromClient[1:]
+			c.Unlock()
+			return e, nil
+		}
+		c.Unlock()
+
+		select {
+		case <-c.received:
+		case <-c.closed:
+			return nil, io.ErrClosedPipe
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
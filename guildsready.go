@@ -0,0 +1,101 @@
+package discordgo
+
+import "time"
+
+const defaultGuildsReadyTimeout = 15 * time.Second
+
+type GuildCreateOrigin int
+
+const (
+	GuildCreateUnknown GuildCreateOrigin = iota
+	GuildCreateJoined
+	GuildCreateInitial
+	GuildCreateRecovered
+)
+
+func (s *Session) trackGuilds(i interface{}) *GuildsReady {
+	s.guildsMu.Lock()
+	defer s.guildsMu.Unlock()
+
+	switch t := i.(type) {
+	case *Ready:
+		if s.guildsReadyTimer != nil {
+			s.guildsReadyTimer.Stop()
+			s.guildsReadyTimer = nil
+		}
+
+		s.guildsGeneration++
+		s.guildsReadySent = false
+		s.pendingGuilds = make(map[string]bool, len(t.Guilds))
+		s.unavailableGuilds = make(map[string]bool)
+		for _, g := range t.Guilds {
+			s.pendingGuilds[g.ID] = true
+		}
+
+		if len(s.pendingGuilds) == 0 {
+			s.guildsReadySent = true
+			return &GuildsReady{}
+		}
+
+		timeout := s.GuildsReadyTimeout
+		if timeout <= 0 {
+			timeout = defaultGuildsReadyTimeout
+		}
+
+		generation := s.guildsGeneration
+		s.guildsReadyTimer = time.AfterFunc(timeout, func() {
+			s.guildsReadyTimedOut(generation)
+		})
+	case *GuildCreate:
+		if t.Guild == nil {
+			return nil
+		}
+
+		switch {
+		case s.pendingGuilds[t.ID]:
+			t.Origin = GuildCreateInitial
+			delete(s.pendingGuilds, t.ID)
+			if len(s.pendingGuilds) == 0 && !s.guildsReadySent {
+				s.guildsReadySent = true
+				if s.guildsReadyTimer != nil {
+					s.guildsReadyTimer.Stop()
+					s.guildsReadyTimer = nil
+				}
+				return &GuildsReady{}
+			}
+		case s.unavailableGuilds[t.ID]:
+			t.Origin = GuildCreateRecovered
+			delete(s.unavailableGuilds, t.ID)
+		default:
+			t.Origin = GuildCreateJoined
+		}
+	case *GuildDelete:
+		if t.Guild != nil && t.Unavailable {
+			if s.unavailableGuilds == nil {
+				s.unavailableGuilds = make(map[string]bool)
+			}
+			s.unavailableGuilds[t.ID] = true
+		}
+	}
+
+	return nil
+}
+
+func (s *Session) guildsReadyTimedOut(generation int) {
+	s.guildsMu.Lock()
+	if generation != s.guildsGeneration || s.guildsReadySent {
+		s.guildsMu.Unlock()
+		return
+	}
+
+	s.guildsReadySent = true
+	s.guildsReadyTimer = nil
+	missing := make([]string, 0, len(s.pendingGuilds))
+	for id := range s.pendingGuilds {
+		missing = append(missing, id)
+	}
+	s.guildsMu.Unlock()
+
+	s.log(LogWarning, "timed out waiting for %d guilds after READY", len(missing))
+	s.handleEvent(guildsReadyEventType, &GuildsReady{TimedOut: true, Missing: missing})
+}
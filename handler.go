@@ -16,6 +16,7 @@ const (
 	entitlementDeleteEventType                   = "ENTITLEMENT_DELETE"
 	entitlementUpdateEventType                   = "ENTITLEMENT_UPDATE"
 	eventEventType                               = "__EVENT__"
+	gatewayFatalCloseEventType                   = "__GATEWAY_FATAL_CLOSE__"
 	guildAuditLogEntryCreateEventType            = "GUILD_AUDIT_LOG_ENTRY_CREATE"
 	guildBanAddEventType                         = "GUILD_BAN_ADD"
 	guildBanRemoveEventType                      = "GUILD_BAN_REMOVE"
@@ -35,11 +36,20 @@ const (
 	guildScheduledEventUpdateEventType           = "GUILD_SCHEDULED_EVENT_UPDATE"
 	guildScheduledEventUserAddEventType          = "GUILD_SCHEDULED_EVENT_USER_ADD"
 	guildScheduledEventUserRemoveEventType       = "GUILD_SCHEDULED_EVENT_USER_REMOVE"
+	guildSoundboardSoundCreateEventType          = "GUILD_SOUNDBOARD_SOUND_CREATE"
+	guildSoundboardSoundDeleteEventType          = "GUILD_SOUNDBOARD_SOUND_DELETE"
+	guildSoundboardSoundUpdateEventType          = "GUILD_SOUNDBOARD_SOUND_UPDATE"
+	guildSoundboardSoundsUpdateEventType         = "GUILD_SOUNDBOARD_SOUNDS_UPDATE"
 	guildUpdateEventType                         = "GUILD_UPDATE"
+	guildsReadyEventType                         = "__GUILDS_READY__"
+	heartbeatAckMissedEventType                  = "__HEARTBEAT_ACK_MISSED__"
+	identifyingEventType                         = "__IDENTIFYING__"
 	integrationCreateEventType                   = "INTEGRATION_CREATE"
 	integrationDeleteEventType                   = "INTEGRATION_DELETE"
 	integrationUpdateEventType                   = "INTEGRATION_UPDATE"
 	interactionCreateEventType                   = "INTERACTION_CREATE"
+	invalidRequestWarningEventType               = "__INVALID_REQUEST_WARNING__"
+	invalidSessionEventType                      = "__INVALID_SESSION__"
 	inviteCreateEventType                        = "INVITE_CREATE"
 	inviteDeleteEventType                        = "INVITE_DELETE"
 	messageCreateEventType                       = "MESSAGE_CREATE"
@@ -55,7 +65,13 @@ const (
 	presencesReplaceEventType                    = "PRESENCES_REPLACE"
 	rateLimitEventType                           = "__RATE_LIMIT__"
 	readyEventType                               = "READY"
+	reconnectAttemptEventType                    = "__RECONNECT_ATTEMPT__"
+	reconnectRequestedEventType                  = "__RECONNECT_REQUESTED__"
+	requestRetryEventType                        = "__REQUEST_RETRY__"
 	resumedEventType                             = "RESUMED"
+	resumingEventType                            = "__RESUMING__"
+	shardReadyEventType                          = "__SHARD_READY__"
+	soundboardSoundsEventType                    = "SOUNDBOARD_SOUNDS"
 	stageInstanceEventCreateEventType            = "STAGE_INSTANCE_EVENT_CREATE"
 	stageInstanceEventDeleteEventType            = "STAGE_INSTANCE_EVENT_DELETE"
 	stageInstanceEventUpdateEventType            = "STAGE_INSTANCE_EVENT_UPDATE"
@@ -183,9 +199,9 @@ func (s *Session) removeEventHandlerInstance(t string, ehi *eventHandlerInstance
 	}
 }
 
-func (s *Session) handle(t string, i interface{}) {
+func (s *Session) handle(t string, i interface{}, synchronous bool) {
 	for _, eh := range s.handlers[t] {
-		if s.SyncEvents {
+		if synchronous {
 			eh.eventHandler.Handle(s, i)
 		} else {
 			go eh.eventHandler.Handle(s, i)
@@ -194,7 +210,7 @@ func (s *Session) handle(t string, i interface{}) {
 
 	if len(s.onceHandlers[t]) > 0 {
 		for _, eh := range s.onceHandlers[t] {
-			if s.SyncEvents {
+			if synchronous {
 				eh.eventHandler.Handle(s, i)
 			} else {
 				go eh.eventHandler.Handle(s, i)
@@ -204,12 +220,28 @@ func (s *Session) handle(t string, i interface{}) {
 	}
 }
 
+func (s *Session) hasEventHandlers() bool {
+	s.handlersMu.RLock()
+	n := len(s.handlers[eventEventType]) + len(s.onceHandlers[eventEventType])
+	s.handlersMu.RUnlock()
+
+	return n > 0 || s.manager != nil && s.manager.hasEventHandlers()
+}
+
 func (s *Session) handleEvent(t string, i interface{}) {
+	s.handleEventSync(t, i, s.SyncEvents)
+}
+
+func (s *Session) handleEventSync(t string, i interface{}, synchronous bool) {
 	s.handlersMu.RLock()
 	defer s.handlersMu.RUnlock()
 	s.onInterface(i)
-	s.handle(interfaceEventType, i)
-	s.handle(t, i)
+	s.handle(interfaceEventType, i, synchronous)
+	s.handle(t, i, synchronous)
+
+	if s.manager != nil {
+		s.manager.handleEvent(s, t, i, synchronous)
+	}
 }
 
 func setGuildIds(g *Guild) {
@@ -232,11 +264,14 @@ func (s *Session) onInterface(i interface{}) {
 		for _, g := range t.Guilds {
 			setGuildIds(g)
 		}
-		s.onReady(t)
 	case *GuildCreate:
 		setGuildIds(t.Guild)
 	case *GuildUpdate:
 		setGuildIds(t.Guild)
+	case *GuildMembersChunk:
+		for _, m := range t.Members {
+			m.GuildID = t.GuildID
+		}
 	case *VoiceServerUpdate:
 		go s.onVoiceServerUpdate(t)
 	case *VoiceStateUpdate:
@@ -246,10 +281,15 @@ func (s *Session) onInterface(i interface{}) {
 	if err != nil {
 		s.log(LogDebug, "error dispatching internal event, %s", err)
 	}
+
+	if t, ok := i.(*GuildMembersChunk); ok {
+		s.onGuildMembersChunk(t)
+	}
 }
 
 func (s *Session) onReady(r *Ready) {
 	s.sessionID = r.SessionID
+	s.resumeGatewayURL = r.ResumeGatewayURL
 }
 
 type applicationCommandPermissionsUpdateEventHandler func(*Session, *ApplicationCommandPermissionsUpdate)
@@ -480,6 +520,18 @@ func (eh eventEventHandler) Handle(s *Session, i interface{}) {
 	}
 }
 
+type gatewayFatalCloseEventHandler func(*Session, *GatewayFatalClose)
+
+func (eh gatewayFatalCloseEventHandler) Type() string {
+	return gatewayFatalCloseEventType
+}
+
+func (eh gatewayFatalCloseEventHandler) Handle(s *Session, i interface{}) {
+	if t, ok := i.(*GatewayFatalClose); ok {
+		eh(s, t)
+	}
+}
+
 type guildAuditLogEntryCreateEventHandler func(*Session, *GuildAuditLogEntryCreate)
 
 func (eh guildAuditLogEntryCreateEventHandler) Type() string {
@@ -784,6 +836,70 @@ func (eh guildScheduledEventUserRemoveEventHandler) Handle(s *Session, i interfa
 	}
 }
 
+type guildSoundboardSoundCreateEventHandler func(*Session, *GuildSoundboardSoundCreate)
+
+func (eh guildSoundboardSoundCreateEventHandler) Type() string {
+	return guildSoundboardSoundCreateEventType
+}
+
+func (eh guildSoundboardSoundCreateEventHandler) New() interface{} {
+	return &GuildSoundboardSoundCreate{}
+}
+
+func (eh guildSoundboardSoundCreateEventHandler) Handle(s *Session, i interface{}) {
+	if t, ok := i.(*GuildSoundboardSoundCreate); ok {
+		eh(s, t)
+	}
+}
+
+type guildSoundboardSoundDeleteEventHandler func(*Session, *GuildSoundboardSoundDelete)
+
+func (eh guildSoundboardSoundDeleteEventHandler) Type() string {
+	return guildSoundboardSoundDeleteEventType
+}
+
+func (eh guildSoundboardSoundDeleteEventHandler) New() interface{} {
+	return &GuildSoundboardSoundDelete{}
+}
+
+func (eh guildSoundboardSoundDeleteEventHandler) Handle(s *Session, i interface{}) {
+	if t, ok := i.(*GuildSoundboardSoundDelete); ok {
+		eh(s, t)
+	}
+}
+
+type guildSoundboardSoundUpdateEventHandler func(*Session, *GuildSoundboardSoundUpdate)
+
+func (eh guildSoundboardSoundUpdateEventHandler) Type() string {
+	return guildSoundboardSoundUpdateEventType
+}
+
+func (eh guildSoundboardSoundUpdateEventHandler) New() interface{} {
+	return &GuildSoundboardSoundUpdate{}
+}
+
+func (eh guildSoundboardSoundUpdateEventHandler) Handle(s *Session, i interface{}) {
+	if t, ok := i.(*GuildSoundboardSoundUpdate); ok {
+		eh(s, t)
+	}
+}
+
+type guildSoundboardSoundsUpdateEventHandler func(*Session, *GuildSoundboardSoundsUpdate)
+
+func (eh guildSoundboardSoundsUpdateEventHandler) Type() string {
+	return guildSoundboardSoundsUpdateEventType
+}
+
+func (eh guildSoundboardSoundsUpdateEventHandler) New() interface{} {
+	return &GuildSoundboardSoundsUpdate{}
+}
+
+func (eh guildSoundboardSoundsUpdateEventHandler) Handle(s *Session, i interface{}) {
+	if t, ok := i.(*GuildSoundboardSoundsUpdate); ok {
+		eh(s, t)
+	}
+}
+
 type guildUpdateEventHandler func(*Session, *GuildUpdate)
 
 func (eh guildUpdateEventHandler) Type() string {
@@ -800,6 +916,42 @@ func (eh guildUpdateEventHandler) Handle(s *Session, i interface{}) {
 	}
 }
 
+type guildsReadyEventHandler func(*Session, *GuildsReady)
+
+func (eh guildsReadyEventHandler) Type() string {
+	return guildsReadyEventType
+}
+
+func (eh guildsReadyEventHandler) Handle(s *Session, i interface{}) {
+	if t, ok := i.(*GuildsReady); ok {
+		eh(s, t)
+	}
+}
+
+type heartbeatAckMissedEventHandler func(*Session, *HeartbeatAckMissed)
+
+func (eh heartbeatAckMissedEventHandler) Type() string {
+	return heartbeatAckMissedEventType
+}
+
+func (eh heartbeatAckMissedEventHandler) Handle(s *Session, i interface{}) {
+	if t, ok := i.(*HeartbeatAckMissed); ok {
+		eh(s, t)
+	}
+}
+
+type identifyingEventHandler func(*Session, *Identifying)
+
+func (eh identifyingEventHandler) Type() string {
+	return identifyingEventType
+}
+
+func (eh identifyingEventHandler) Handle(s *Session, i interface{}) {
+	if t, ok := i.(*Identifying); ok {
+		eh(s, t)
+	}
+}
+
 type integrationCreateEventHandler func(*Session, *IntegrationCreate)
 
 func (eh integrationCreateEventHandler) Type() string {
@@ -864,6 +1016,30 @@ func (eh interactionCreateEventHandler) Handle(s *Session, i interface{}) {
 	}
 }
 
+type invalidRequestWarningEventHandler func(*Session, *InvalidRequestWarning)
+
+func (eh invalidRequestWarningEventHandler) Type() string {
+	return invalidRequestWarningEventType
+}
+
+func (eh invalidRequestWarningEventHandler) Handle(s *Session, i interface{}) {
+	if t, ok := i.(*InvalidRequestWarning); ok {
+		eh(s, t)
+	}
+}
+
+type invalidSessionEventHandler func(*Session, *InvalidSession)
+
+func (eh invalidSessionEventHandler) Type() string {
+	return invalidSessionEventType
+}
+
+func (eh invalidSessionEventHandler) Handle(s *Session, i interface{}) {
+	if t, ok := i.(*InvalidSession); ok {
+		eh(s, t)
+	}
+}
+
 type inviteCreateEventHandler func(*Session, *InviteCreate)
 
 func (eh inviteCreateEventHandler) Type() string {
@@ -1100,6 +1276,42 @@ func (eh readyEventHandler) Handle(s *Session, i interface{}) {
 	}
 }
 
+type reconnectAttemptEventHandler func(*Session, *ReconnectAttempt)
+
+func (eh reconnectAttemptEventHandler) Type() string {
+	return reconnectAttemptEventType
+}
+
+func (eh reconnectAttemptEventHandler) Handle(s *Session, i interface{}) {
+	if t, ok := i.(*ReconnectAttempt); ok {
+		eh(s, t)
+	}
+}
+
+type reconnectRequestedEventHandler func(*Session, *ReconnectRequested)
+
+func (eh reconnectRequestedEventHandler) Type() string {
+	return reconnectRequestedEventType
+}
+
+func (eh reconnectRequestedEventHandler) Handle(s *Session, i interface{}) {
+	if t, ok := i.(*ReconnectRequested); ok {
+		eh(s, t)
+	}
+}
+
+type requestRetryEventHandler func(*Session, *RequestRetry)
+
+func (eh requestRetryEventHandler) Type() string {
+	return requestRetryEventType
+}
+
+func (eh requestRetryEventHandler) Handle(s *Session, i interface{}) {
+	if t, ok := i.(*RequestRetry); ok {
+		eh(s, t)
+	}
+}
+
 type resumedEventHandler func(*Session, *Resumed)
 
 func (eh resumedEventHandler) Type() string {
@@ -1116,6 +1328,46 @@ func (eh resumedEventHandler) Handle(s *Session, i interface{}) {
 	}
 }
 
+type resumingEventHandler func(*Session, *Resuming)
+
+func (eh resumingEventHandler) Type() string {
+	return resumingEventType
+}
+
+func (eh resumingEventHandler) Handle(s *Session, i interface{}) {
+	if t, ok := i.(*Resuming); ok {
+		eh(s, t)
+	}
+}
+
+type shardReadyEventHandler func(*Session, *ShardReady)
+
+func (eh shardReadyEventHandler) Type() string {
+	return shardReadyEventType
+}
+
+func (eh shardReadyEventHandler) Handle(s *Session, i interface{}) {
+	if t, ok := i.(*ShardReady); ok {
+		eh(s, t)
+	}
+}
+
+type soundboardSoundsEventHandler func(*Session, *SoundboardSounds)
+
+func (eh soundboardSoundsEventHandler) Type() string {
+	return soundboardSoundsEventType
+}
+
+func (eh soundboardSoundsEventHandler) New() interface{} {
+	return &SoundboardSounds{}
+}
+
+func (eh soundboardSoundsEventHandler) Handle(s *Session, i interface{}) {
+	if t, ok := i.(*SoundboardSounds); ok {
+		eh(s, t)
+	}
+}
+
 type stageInstanceEventCreateEventHandler func(*Session, *StageInstanceEventCreate)
 
 func (eh stageInstanceEventCreateEventHandler) Type() string {
@@ -1422,6 +1674,8 @@ func handlerForInterface(handler interface{}) EventHandler {
 		return entitlementUpdateEventHandler(v)
 	case func(*Session, *Event):
 		return eventEventHandler(v)
+	case func(*Session, *GatewayFatalClose):
+		return gatewayFatalCloseEventHandler(v)
 	case func(*Session, *GuildAuditLogEntryCreate):
 		return guildAuditLogEntryCreateEventHandler(v)
 	case func(*Session, *GuildBanAdd):
@@ -1460,8 +1714,22 @@ func handlerForInterface(handler interface{}) EventHandler {
 		return guildScheduledEventUserAddEventHandler(v)
 	case func(*Session, *GuildScheduledEventUserRemove):
 		return guildScheduledEventUserRemoveEventHandler(v)
+	case func(*Session, *GuildSoundboardSoundCreate):
+		return guildSoundboardSoundCreateEventHandler(v)
+	case func(*Session, *GuildSoundboardSoundDelete):
+		return guildSoundboardSoundDeleteEventHandler(v)
+	case func(*Session, *GuildSoundboardSoundUpdate):
+		return guildSoundboardSoundUpdateEventHandler(v)
+	case func(*Session, *GuildSoundboardSoundsUpdate):
+		return guildSoundboardSoundsUpdateEventHandler(v)
 	case func(*Session, *GuildUpdate):
 		return guildUpdateEventHandler(v)
+	case func(*Session, *GuildsReady):
+		return guildsReadyEventHandler(v)
+	case func(*Session, *HeartbeatAckMissed):
+		return heartbeatAckMissedEventHandler(v)
+	case func(*Session, *Identifying):
+		return identifyingEventHandler(v)
 	case func(*Session, *IntegrationCreate):
 		return integrationCreateEventHandler(v)
 	case func(*Session, *IntegrationDelete):
@@ -1470,6 +1738,10 @@ func handlerForInterface(handler interface{}) EventHandler {
 		return integrationUpdateEventHandler(v)
 	case func(*Session, *InteractionCreate):
 		return interactionCreateEventHandler(v)
+	case func(*Session, *InvalidRequestWarning):
+		return invalidRequestWarningEventHandler(v)
+	case func(*Session, *InvalidSession):
+		return invalidSessionEventHandler(v)
 	case func(*Session, *InviteCreate):
 		return inviteCreateEventHandler(v)
 	case func(*Session, *InviteDelete):
@@ -1500,8 +1772,20 @@ func handlerForInterface(handler interface{}) EventHandler {
 		return rateLimitEventHandler(v)
 	case func(*Session, *Ready):
 		return readyEventHandler(v)
+	case func(*Session, *ReconnectAttempt):
+		return reconnectAttemptEventHandler(v)
+	case func(*Session, *ReconnectRequested):
+		return reconnectRequestedEventHandler(v)
+	case func(*Session, *RequestRetry):
+		return requestRetryEventHandler(v)
 	case func(*Session, *Resumed):
 		return resumedEventHandler(v)
+	case func(*Session, *Resuming):
+		return resumingEventHandler(v)
+	case func(*Session, *ShardReady):
+		return shardReadyEventHandler(v)
+	case func(*Session, *SoundboardSounds):
+		return soundboardSoundsEventHandler(v)
 	case func(*Session, *StageInstanceEventCreate):
 		return stageInstanceEventCreateEventHandler(v)
 	case func(*Session, *StageInstanceEventDelete):
@@ -1573,6 +1857,10 @@ func init() {
 	registerInterfaceProvider(guildScheduledEventUpdateEventHandler(nil))
 	registerInterfaceProvider(guildScheduledEventUserAddEventHandler(nil))
 	registerInterfaceProvider(guildScheduledEventUserRemoveEventHandler(nil))
+	registerInterfaceProvider(guildSoundboardSoundCreateEventHandler(nil))
+	registerInterfaceProvider(guildSoundboardSoundDeleteEventHandler(nil))
+	registerInterfaceProvider(guildSoundboardSoundUpdateEventHandler(nil))
+	registerInterfaceProvider(guildSoundboardSoundsUpdateEventHandler(nil))
 	registerInterfaceProvider(guildUpdateEventHandler(nil))
 	registerInterfaceProvider(integrationCreateEventHandler(nil))
 	registerInterfaceProvider(integrationDeleteEventHandler(nil))
@@ -1593,6 +1881,7 @@ func init() {
 	registerInterfaceProvider(presencesReplaceEventHandler(nil))
 	registerInterfaceProvider(readyEventHandler(nil))
 	registerInterfaceProvider(resumedEventHandler(nil))
+	registerInterfaceProvider(soundboardSoundsEventHandler(nil))
 	registerInterfaceProvider(stageInstanceEventCreateEventHandler(nil))
 	registerInterfaceProvider(stageInstanceEventDeleteEventHandler(nil))
 	registerInterfaceProvider(stageInstanceEventUpdateEventHandler(nil))
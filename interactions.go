@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"crypto/ed25519"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -199,12 +198,16 @@ type interaction Interaction
 
 type rawInteraction struct {
 	interaction
-	Data json.RawMessage `json:"data"`
+	Data rawValue `json:"data"`
 }
 
 func (i *Interaction) UnmarshalJSON(raw []byte) error {
+	return i.unmarshalFrom(jsonSource(raw))
+}
+
+func (i *Interaction) unmarshalFrom(decode decodeFunc) error {
 	var tmp rawInteraction
-	if err := Unmarshal(raw, &tmp); err != nil {
+	if err := decode(&tmp); err != nil {
 		return err
 	}
 
@@ -215,19 +218,19 @@ func (i *Interaction) UnmarshalJSON(raw []byte) error {
 	switch tmp.Type {
 	case InteractionApplicationCommand, InteractionApplicationCommandAutocomplete:
 		var v ApplicationCommandInteractionData
-		if err := Unmarshal(tmp.Data, &v); err != nil {
+		if err := tmp.Data.decode(&v); err != nil {
 			return err
 		}
 		parsed = v
 	case InteractionMessageComponent:
 		var v MessageComponentInteractionData
-		if err := Unmarshal(tmp.Data, &v); err != nil {
+		if err := tmp.Data.decode(&v); err != nil {
 			return err
 		}
 		parsed = v
 	case InteractionModalSubmit:
 		var v ModalSubmitInteractionData
-		if err := Unmarshal(tmp.Data, &v); err != nil {
+		if err := tmp.Data.decode(&v); err != nil {
 			return err
 		}
 		parsed = v
@@ -325,16 +328,21 @@ func (ModalSubmitInteractionData) Type() InteractionType {
 }
 
 func (d *ModalSubmitInteractionData) UnmarshalJSON(data []byte) error {
+	return d.unmarshalFrom(jsonSource(data))
+}
+
+func (d *ModalSubmitInteractionData) unmarshalFrom(decode decodeFunc) error {
+	type modalSubmitInteractionData ModalSubmitInteractionData
 	var v struct {
-		ModalSubmitInteractionData
+		modalSubmitInteractionData
 		RawComponents []unmarshalableMessageComponent `json:"components"`
 	}
 
-	if err := Unmarshal(data, &v); err != nil {
+	if err := decode(&v); err != nil {
 		return err
 	}
 
-	*d = ModalSubmitInteractionData(v.ModalSubmitInteractionData)
+	*d = ModalSubmitInteractionData(v.modalSubmitInteractionData)
 	d.Components = make([]MessageComponent, len(v.RawComponents))
 
 	for i, component := range v.RawComponents {
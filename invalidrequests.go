@@ -0,0 +1,146 @@
+package discordgo
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type InvalidRequestBreaker struct {
+	sync.Mutex
+	Window  time.Duration
+	TripAt  int
+	WarnAt  []int
+	hits    []time.Time
+	warned  int
+	tripped bool
+}
+
+func NewInvalidRequestBreaker() *InvalidRequestBreaker {
+	return &InvalidRequestBreaker{
+		Window: 10 * time.Minute,
+		TripAt: 9000,
+		WarnAt: []int{5000, 7500, 8500},
+	}
+}
+
+func (b *InvalidRequestBreaker) Count() int {
+	b.Lock()
+	defer b.Unlock()
+
+	b.prune(time.Now())
+	return len(b.hits)
+}
+
+func (b *InvalidRequestBreaker) Open() bool {
+	b.Lock()
+	defer b.Unlock()
+
+	b.prune(time.Now())
+	return b.TripAt > 0 && len(b.hits) >= b.TripAt
+}
+
+func (b *InvalidRequestBreaker) Reset() {
+	b.Lock()
+	defer b.Unlock()
+
+	b.hits = nil
+	b.warned = 0
+	b.tripped = false
+}
+
+func (b *InvalidRequestBreaker) record() (count int, warn bool, tripped bool) {
+	b.Lock()
+	defer b.Unlock()
+
+	now := time.Now()
+	b.prune(now)
+	b.hits = append(b.hits, now)
+	count = len(b.hits)
+
+	for _, n := range b.WarnAt {
+		if count >= n && n > b.warned {
+			b.warned = n
+			warn = true
+		}
+	}
+
+	if b.TripAt > 0 && count >= b.TripAt && !b.tripped {
+		b.tripped = true
+		tripped = true
+	}
+
+	return count, warn, tripped
+}
+
+func (b *InvalidRequestBreaker) prune(now time.Time) {
+	cutoff := now.Add(-b.Window)
+
+	i := 0
+	for i < len(b.hits) && !b.hits[i].After(cutoff) {
+		i++
+	}
+	if i > 0 {
+		b.hits = append(b.hits[:0], b.hits[i:]...)
+	}
+
+	count := len(b.hits)
+	if count < b.warned {
+		b.warned = 0
+		for _, n := range b.WarnAt {
+			if count >= n && n > b.warned {
+				b.warned = n
+			}
+		}
+	}
+
+	if b.tripped && count < b.TripAt {
+		b.tripped = false
+	}
+}
+
+func invalidResponse(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return true
+	case http.StatusTooManyRequests:
+		return resp.Header.Get("X-RateLimit-Scope") != "shared"
+	}
+
+	return false
+}
+
+func (s *Session) checkInvalidRequests() error {
+	b := s.InvalidRequests
+	if b == nil || !b.Open() {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %d invalid responses in the last %s", ErrInvalidRequestLimit, b.Count(), b.Window)
+}
+
+func (s *Session) recordInvalidRequest(resp *http.Response) {
+	b := s.InvalidRequests
+	if b == nil || !invalidResponse(resp) {
+		return
+	}
+
+	count, warn, tripped := b.record()
+	if !warn && !tripped {
+		return
+	}
+
+	if tripped {
+		s.log(LogError, "%d invalid responses in the last %s, rejecting requests until the count drops", count, b.Window)
+	} else {
+		s.log(LogWarning, "%d invalid responses in the last %s, requests will be rejected at %d", count, b.Window, b.TripAt)
+	}
+
+	s.handleEvent(invalidRequestWarningEventType, &InvalidRequestWarning{
+		Count:   count,
+		TripAt:  b.TripAt,
+		Window:  b.Window,
+		Tripped: tripped,
+	})
+}
@@ -0,0 +1,543 @@
+package discordgo
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type PageDirection int
+
+const (
+	PageAfter PageDirection = iota
+	PageBefore
+)
+
+type PageOptions struct {
+	Direction PageDirection
+	From      string
+	FromTime  time.Time
+	PageSize  int
+	Limit     int
+}
+
+func (opts PageOptions) bounded(before, after *time.Time, pageSize int) (PageOptions, error) {
+	if pageSize > 0 && opts.PageSize <= 0 {
+		opts.PageSize = pageSize
+	}
+
+	var from *time.Time
+	switch {
+	case before != nil && after != nil:
+		return opts, ErrIteratorBounds
+	case before != nil:
+		if opts.Direction != PageBefore {
+			return opts, ErrIteratorDirection
+		}
+		from = before
+	case after != nil:
+		if opts.Direction != PageAfter {
+			return opts, ErrIteratorDirection
+		}
+		from = after
+	default:
+		return opts, nil
+	}
+
+	if opts.From != "" || !opts.FromTime.IsZero() {
+		return opts, ErrIteratorBounds
+	}
+
+	opts.FromTime = *from
+	return opts, nil
+}
+
+type pager struct {
+	ctx    context.Context
+	limit  int
+	count  int
+	buffer []interface{}
+	done   bool
+	fetch  func(ctx context.Context) ([]interface{}, bool, error)
+}
+
+func newPager(ctx context.Context, opts PageOptions, fetch func(ctx context.Context) ([]interface{}, bool, error)) pager {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return pager{ctx: ctx, limit: opts.Limit, fetch: fetch}
+}
+
+func (p *pager) next() (interface{}, error) {
+	if p.limit > 0 && p.count >= p.limit {
+		return nil, ErrIteratorDone
+	}
+
+	for len(p.buffer) == 0 {
+		if p.done {
+			return nil, ErrIteratorDone
+		}
+
+		if err := p.ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		items, more, err := p.fetch(p.ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		p.buffer = items
+		p.done = !more || len(items) == 0
+	}
+
+	item := p.buffer[0]
+	p.buffer = p.buffer[1:]
+	p.count++
+
+	return item, nil
+}
+
+type snowflakeCursor struct {
+	direction PageDirection
+	cursor    string
+	pageSize  int
+}
+
+func newSnowflakeCursor(opts PageOptions, maxPageSize int) *snowflakeCursor {
+	c := &snowflakeCursor{
+		direction: opts.Direction,
+		cursor:    opts.From,
+		pageSize:  opts.PageSize,
+	}
+
+	if c.pageSize <= 0 || c.pageSize > maxPageSize {
+		c.pageSize = maxPageSize
+	}
+
+	if c.cursor == "" {
+		switch {
+		case !opts.FromTime.IsZero():
+			c.cursor = SnowflakeFromTime(opts.FromTime)
+		case c.direction == PageBefore:
+			c.cursor = SnowflakeFromTime(time.Now().Add(time.Minute))
+		default:
+			c.cursor = "0"
+		}
+	}
+
+	return c
+}
+
+func (c *snowflakeCursor) before() string {
+	if c.direction == PageBefore {
+		return c.cursor
+	}
+	return ""
+}
+
+func (c *snowflakeCursor) after() string {
+	if c.direction == PageAfter {
+		return c.cursor
+	}
+	return ""
+}
+
+func (c *snowflakeCursor) setQuery(v url.Values) {
+	if before := c.before(); before != "" {
+		v.Set("before", before)
+	}
+	if after := c.after(); after != "" {
+		v.Set("after", after)
+	}
+	v.Set("limit", strconv.Itoa(c.pageSize))
+}
+
+func (c *snowflakeCursor) advance(items []interface{}, id func(interface{}) string) bool {
+	sort.SliceStable(items, func(i, j int) bool {
+		if c.direction == PageBefore {
+			return snowflakeLess(id(items[j]), id(items[i]))
+		}
+		return snowflakeLess(id(items[i]), id(items[j]))
+	})
+
+	if len(items) > 0 {
+		c.cursor = id(items[len(items)-1])
+	}
+
+	return len(items) >= c.pageSize
+}
+
+func snowflakeLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+type MemberIterator struct {
+	pager
+}
+
+func (it *MemberIterator) Next() (*Member, error) {
+	v, err := it.next()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Member), nil
+}
+
+func (s *Session) GuildMembersIterator(ctx context.Context, guildID string, opts PageOptions) *MemberIterator {
+	c := newSnowflakeCursor(opts, 1000)
+	return &MemberIterator{newPager(ctx, opts, func(ctx context.Context) ([]interface{}, bool, error) {
+		if c.direction != PageAfter {
+			return nil, false, ErrIteratorDirection
+		}
+
+		st, err := s.GuildMembers(guildID, c.after(), c.pageSize, WithContext(ctx))
+		if err != nil {
+			return nil, false, err
+		}
+
+		items := make([]interface{}, len(st))
+		for i, m := range st {
+			m.GuildID = guildID
+			items[i] = m
+		}
+		return items, c.advance(items, func(v interface{}) string { return v.(*Member).User.ID }), nil
+	})}
+}
+
+type GuildBanIterator struct {
+	pager
+}
+
+func (it *GuildBanIterator) Next() (*GuildBan, error) {
+	v, err := it.next()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*GuildBan), nil
+}
+
+func (s *Session) GuildBansIterator(ctx context.Context, guildID string, opts PageOptions) *GuildBanIterator {
+	c := newSnowflakeCursor(opts, 1000)
+	return &GuildBanIterator{newPager(ctx, opts, func(ctx context.Context) ([]interface{}, bool, error) {
+		st, err := s.GuildBans(guildID, c.pageSize, c.before(), c.after(), WithContext(ctx))
+		if err != nil {
+			return nil, false, err
+		}
+
+		items := make([]interface{}, len(st))
+		for i, b := range st {
+			items[i] = b
+		}
+		return items, c.advance(items, func(v interface{}) string { return v.(*GuildBan).User.ID }), nil
+	})}
+}
+
+type MessageIterator struct {
+	pager
+}
+
+func (it *MessageIterator) Next() (*Message, error) {
+	v, err := it.next()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Message), nil
+}
+
+func (s *Session) ChannelMessagesIterator(ctx context.Context, channelID string, opts PageOptions) *MessageIterator {
+	c := newSnowflakeCursor(opts, 100)
+	return &MessageIterator{newPager(ctx, opts, func(ctx context.Context) ([]interface{}, bool, error) {
+		st, err := s.ChannelMessages(channelID, c.pageSize, c.before(), c.after(), "", WithContext(ctx))
+		if err != nil {
+			return nil, false, err
+		}
+
+		items := make([]interface{}, len(st))
+		for i, m := range st {
+			items[i] = m
+		}
+		return items, c.advance(items, func(v interface{}) string { return v.(*Message).ID }), nil
+	})}
+}
+
+type UserIterator struct {
+	pager
+}
+
+func (it *UserIterator) Next() (*User, error) {
+	v, err := it.next()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*User), nil
+}
+
+func (s *Session) MessageReactionsIterator(ctx context.Context, channelID, messageID, emojiID string, opts PageOptions) *UserIterator {
+	c := newSnowflakeCursor(opts, 100)
+	return &UserIterator{newPager(ctx, opts, func(ctx context.Context) ([]interface{}, bool, error) {
+		if c.direction != PageAfter {
+			return nil, false, ErrIteratorDirection
+		}
+
+		st, err := s.MessageReactions(channelID, messageID, emojiID, c.pageSize, "", c.after(), WithContext(ctx))
+		if err != nil {
+			return nil, false, err
+		}
+
+		items := make([]interface{}, len(st))
+		for i, u := range st {
+			items[i] = u
+		}
+		return items, c.advance(items, func(v interface{}) string { return v.(*User).ID }), nil
+	})}
+}
+
+type AuditLogEntryIterator struct {
+	pager
+}
+
+func (it *AuditLogEntryIterator) Next() (*AuditLogEntry, error) {
+	v, err := it.next()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*AuditLogEntry), nil
+}
+
+func (s *Session) GuildAuditLogIterator(ctx context.Context, guildID, userID string, actionType int, opts PageOptions) *AuditLogEntryIterator {
+	c := newSnowflakeCursor(opts, 100)
+	return &AuditLogEntryIterator{newPager(ctx, opts, func(ctx context.Context) ([]interface{}, bool, error) {
+		if c.direction != PageBefore {
+			return nil, false, ErrIteratorDirection
+		}
+
+		st, err := s.GuildAuditLog(guildID, userID, c.before(), actionType, c.pageSize, WithContext(ctx))
+		if err != nil {
+			return nil, false, err
+		}
+
+		items := make([]interface{}, len(st.AuditLogEntries))
+		for i, e := range st.AuditLogEntries {
+			items[i] = e
+		}
+		return items, c.advance(items, func(v interface{}) string { return v.(*AuditLogEntry).ID }), nil
+	})}
+}
+
+type ThreadIterator struct {
+	pager
+}
+
+func (it *ThreadIterator) Next() (*Channel, error) {
+	v, err := it.next()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Channel), nil
+}
+
+func (s *Session) ThreadsArchivedIterator(ctx context.Context, channelID string, opts PageOptions) *ThreadIterator {
+	return s.threadsIterator(ctx, channelID, opts, s.ThreadsArchived)
+}
+
+func (s *Session) ThreadsPrivateArchivedIterator(ctx context.Context, channelID string, opts PageOptions) *ThreadIterator {
+	return s.threadsIterator(ctx, channelID, opts, s.ThreadsPrivateArchived)
+}
+
+func (s *Session) ThreadsPrivateJoinedArchivedIterator(ctx context.Context, channelID string, opts PageOptions) *ThreadIterator {
+	return s.threadsIterator(ctx, channelID, opts, s.ThreadsPrivateJoinedArchived)
+}
+
+func (s *Session) threadsIterator(ctx context.Context, channelID string, opts PageOptions, list func(string, *time.Time, int, ...RequestOption) (*ThreadsList, error)) *ThreadIterator {
+	var before *time.Time
+	switch {
+	case !opts.FromTime.IsZero():
+		before = &opts.FromTime
+	case opts.From != "":
+		if t, err := SnowflakeTimestamp(opts.From); err == nil {
+			before = &t
+		}
+	}
+
+	pageSize := opts.PageSize
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 100
+	}
+
+	return &ThreadIterator{newPager(ctx, opts, func(ctx context.Context) ([]interface{}, bool, error) {
+		if opts.Direction != PageBefore {
+			return nil, false, ErrIteratorDirection
+		}
+
+		st, err := list(channelID, before, pageSize, WithContext(ctx))
+		if err != nil {
+			return nil, false, err
+		}
+
+		items := make([]interface{}, len(st.Threads))
+		for i, t := range st.Threads {
+			items[i] = t
+		}
+
+		if n := len(st.Threads); n > 0 && st.Threads[n-1].ThreadMetadata != nil {
+			archived := st.Threads[n-1].ThreadMetadata.ArchiveTimestamp
+			before = &archived
+		}
+		return items, st.HasMore, nil
+	})}
+}
+
+type ThreadMemberIterator struct {
+	pager
+}
+
+func (it *ThreadMemberIterator) Next() (*ThreadMember, error) {
+	v, err := it.next()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*ThreadMember), nil
+}
+
+func (s *Session) ThreadMembersIterator(ctx context.Context, threadID string, withMember bool, opts PageOptions) *ThreadMemberIterator {
+	c := newSnowflakeCursor(opts, 100)
+	return &ThreadMemberIterator{newPager(ctx, opts, func(ctx context.Context) ([]interface{}, bool, error) {
+		if c.direction != PageAfter {
+			return nil, false, ErrIteratorDirection
+		}
+
+		st, err := s.ThreadMembers(threadID, c.pageSize, withMember, c.after(), WithContext(ctx))
+		if err != nil {
+			return nil, false, err
+		}
+
+		items := make([]interface{}, len(st))
+		for i, m := range st {
+			items[i] = m
+		}
+		return items, c.advance(items, func(v interface{}) string { return v.(*ThreadMember).UserID }), nil
+	})}
+}
+
+type GuildScheduledEventUserIterator struct {
+	pager
+}
+
+func (it *GuildScheduledEventUserIterator) Next() (*GuildScheduledEventUser, error) {
+	v, err := it.next()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*GuildScheduledEventUser), nil
+}
+
+func (s *Session) GuildScheduledEventUsersIterator(ctx context.Context, guildID, eventID string, withMember bool, opts PageOptions) *GuildScheduledEventUserIterator {
+	c := newSnowflakeCursor(opts, 100)
+	return &GuildScheduledEventUserIterator{newPager(ctx, opts, func(ctx context.Context) ([]interface{}, bool, error) {
+		st, err := s.GuildScheduledEventUsers(guildID, eventID, c.pageSize, withMember, c.before(), c.after(), WithContext(ctx))
+		if err != nil {
+			return nil, false, err
+		}
+
+		items := make([]interface{}, len(st))
+		for i, u := range st {
+			items[i] = u
+		}
+		return items, c.advance(items, func(v interface{}) string { return v.(*GuildScheduledEventUser).User.ID }), nil
+	})}
+}
+
+type EntitlementIterator struct {
+	pager
+}
+
+func (it *EntitlementIterator) Next() (*Entitlement, error) {
+	v, err := it.next()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Entitlement), nil
+}
+
+func (s *Session) EntitlementsIterator(ctx context.Context, appID string, filterOptions *EntitlementFilterOptions, opts PageOptions) *EntitlementIterator {
+	var filter EntitlementFilterOptions
+	if filterOptions != nil {
+		filter = *filterOptions
+	}
+
+	opts, err := opts.bounded(filter.Before, filter.After, filter.Limit)
+	filter.Before, filter.After, filter.Limit = nil, nil, 0
+
+	c := newSnowflakeCursor(opts, 100)
+	return &EntitlementIterator{newPager(ctx, opts, func(ctx context.Context) ([]interface{}, bool, error) {
+		if err != nil {
+			return nil, false, err
+		}
+
+		endpoint := EndpointEntitlements(appID)
+
+		queryParams := entitlementsQuery(&filter)
+		c.setQuery(queryParams)
+
+		body, err := s.RequestWithBucketID("GET", endpoint+"?"+queryParams.Encode(), nil, endpoint, WithContext(ctx))
+		if err != nil {
+			return nil, false, err
+		}
+
+		var st []*Entitlement
+		if err = unmarshal(body, &st); err != nil {
+			return nil, false, err
+		}
+
+		items := make([]interface{}, len(st))
+		for i, e := range st {
+			items[i] = e
+		}
+		return items, c.advance(items, func(v interface{}) string { return v.(*Entitlement).ID }), nil
+	})}
+}
+
+type SubscriptionIterator struct {
+	pager
+}
+
+func (it *SubscriptionIterator) Next() (*Subscription, error) {
+	v, err := it.next()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Subscription), nil
+}
+
+func (s *Session) SubscriptionsIterator(ctx context.Context, skuID, userID string, opts PageOptions) *SubscriptionIterator {
+	c := newSnowflakeCursor(opts, 100)
+	return &SubscriptionIterator{newPager(ctx, opts, func(ctx context.Context) ([]interface{}, bool, error) {
+		endpoint := EndpointSubscriptions(skuID)
+
+		queryParams := subscriptionsQuery(userID, nil, nil, 0)
+		c.setQuery(queryParams)
+
+		body, err := s.RequestWithBucketID("GET", endpoint+"?"+queryParams.Encode(), nil, endpoint, WithContext(ctx))
+		if err != nil {
+			return nil, false, err
+		}
+
+		var st []*Subscription
+		if err = unmarshal(body, &st); err != nil {
+			return nil, false, err
+		}
+
+		items := make([]interface{}, len(st))
+		for i, sub := range st {
+			items[i] = sub
+		}
+		return items, c.advance(items, func(v interface{}) string { return v.(*Subscription).ID }), nil
+	})}
+}
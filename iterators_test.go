@@ -0,0 +1,36 @@
+package discordgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPageOptionsBounded(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	opts, err := PageOptions{Direction: PageBefore}.bounded(&at, nil, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opts.FromTime.Equal(at) || opts.PageSize != 50 {
+		t.Fatalf("before bound was not honoured: %+v", opts)
+	}
+
+	opts, err = PageOptions{Direction: PageAfter, PageSize: 10}.bounded(nil, &at, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opts.FromTime.Equal(at) || opts.PageSize != 10 {
+		t.Fatalf("after bound was not honoured: %+v", opts)
+	}
+
+	if _, err := (PageOptions{Direction: PageAfter}).bounded(&at, nil, 0); err != ErrIteratorDirection {
+		t.Fatalf("got %v, want %v", err, ErrIteratorDirection)
+	}
+	if _, err := (PageOptions{Direction: PageBefore}).bounded(&at, &at, 0); err != ErrIteratorBounds {
+		t.Fatalf("got %v, want %v", err, ErrIteratorBounds)
+	}
+	if _, err := (PageOptions{Direction: PageBefore, From: "1"}).bounded(&at, nil, 0); err != ErrIteratorBounds {
+		t.Fatalf("got %v, want %v", err, ErrIteratorBounds)
+	}
+}
@@ -71,13 +71,17 @@ type Message struct {
 }
 
 func (m *Message) UnmarshalJSON(data []byte) error {
+	return m.unmarshalFrom(jsonSource(data))
+}
+
+func (m *Message) unmarshalFrom(decode decodeFunc) error {
 	type Alias Message
 	var temp struct {
 		Alias
 		RawComponents []unmarshalableMessageComponent `json:"components"`
 	}
 
-	if err := Unmarshal(data, &temp); err != nil {
+	if err := decode(&temp); err != nil {
 		return err
 	}
 
@@ -480,6 +484,21 @@ var (
 	ErrWSAlreadyOpen                = errors.New("websocket connection is already open, cannot open a new connection while the current one is active")
 	ErrWSNotFound                   = errors.New("no active websocket connection found, please establish a connection before attempting this action")
 	ErrWSShardBounds                = errors.New("invalid ShardID or ShardCount: ShardID must be less than ShardCount")
+	ErrZstdUnavailable              = errors.New("zstd-stream compression requires a decompressor, please assign discordgo.NewZstdReader before opening the session")
+	ErrETFMalformed                 = errors.New("malformed erlang term format payload")
+	ErrUnknownCompression           = errors.New("unknown gateway compression: it must be one of zlib-stream or zstd-stream")
+	ErrShardStartLimit              = errors.New("not enough remaining session starts to identify every shard, wait for the session start limit to reset")
+	ErrGatewayRateLimiterConfig     = errors.New("invalid gateway rate limiter configuration, Limit and Interval must be positive and Reserved must be below Limit")
+	ErrGatewayRateLimited           = errors.New("gateway command rate limit reached, wait for the limit to reset before sending more commands")
+	ErrRateLimitWaitExceeded        = errors.New("predicted rate limit wait exceeds the configured maximum, the request was not sent")
+	ErrInvalidRequestLimit          = errors.New("too many invalid responses in the current window, the request was rejected locally to avoid a temporary ban")
+	ErrCoordinatorClosed            = errors.New("rate limit coordinator is closed, create a new coordinator to serve again")
+	ErrCoordinatorUnavailable       = errors.New("rate limit coordinator is not connected, falling back to the local rate limiter while reconnecting")
+	ErrIteratorDone                 = errors.New("iterator has no more items to return")
+	ErrIteratorDirection            = errors.New("this endpoint cannot be paged in the requested direction")
+	ErrIteratorBounds               = errors.New("an iterator can only start from one point, set either the page options or a single before or after bound")
+	ErrStickerFileMissing           = errors.New("sticker file is required, set StickerParams.File before creating a sticker")
+	ErrBulkBanUsersBounds           = errors.New("invalid bulk ban: it must contain between 1 and 200 user IDs")
 	ErrNilState                     = errors.New("state not found, please ensure that the session is properly initialized using discordgo.New() or manually assign Session.State")
 	ErrStateNotFound                = errors.New("state cache not found, the session might not be initialized correctly or might have expired")
 	ErrMessageIncompletePermissions = errors.New("message incomplete: unable to determine permissions for this action due to missing information")
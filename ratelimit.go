@@ -1,6 +1,7 @@
 package discordgo
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"net/http"
@@ -17,16 +18,26 @@ type customRateLimit struct {
 	reset    time.Duration
 }
 
+type RESTRateLimiter interface {
+	LockBucket(bucketID string) *Bucket
+	LockBucketObject(b *Bucket) *Bucket
+	LockBucketContext(ctx context.Context, bucketID string, maxWait time.Duration) (*Bucket, error)
+	LockBucketObjectContext(ctx context.Context, b *Bucket, maxWait time.Duration) (*Bucket, error)
+	Release(b *Bucket, headers http.Header) error
+}
+
 type RateLimiter struct {
 	sync.Mutex
 	global           *int64
 	buckets          map[string]*Bucket
+	routes           map[string]string
 	customRateLimits []*customRateLimit
 }
 
 func NewRatelimiter() *RateLimiter {
 	return &RateLimiter{
 		buckets: make(map[string]*Bucket),
+		routes:  make(map[string]string),
 		global:  new(int64),
 		customRateLimits: []*customRateLimit{
 			{
@@ -42,6 +53,10 @@ func (r *RateLimiter) GetBucket(key string) *Bucket {
 	r.Lock()
 	defer r.Unlock()
 
+	if hash, ok := r.routes[key]; ok {
+		key = bucketKey(hash, key)
+	}
+
 	if bucket, ok := r.buckets[key]; ok {
 		return bucket
 	}
@@ -50,6 +65,7 @@ func (r *RateLimiter) GetBucket(key string) *Bucket {
 		Remaining: 1,
 		Key:       key,
 		global:    r.global,
+		limiter:   r,
 	}
 
 	for _, rl := range r.customRateLimits {
@@ -63,6 +79,58 @@ func (r *RateLimiter) GetBucket(key string) *Bucket {
 	return b
 }
 
+func (r *RateLimiter) learnRoute(route, hash string, from *Bucket) {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.routes == nil {
+		r.routes = make(map[string]string)
+	}
+
+	if r.routes[route] == hash {
+		return
+	}
+	r.routes[route] = hash
+
+	key := bucketKey(hash, route)
+	if _, ok := r.buckets[key]; !ok {
+		r.buckets[key] = &Bucket{
+			Key:       key,
+			Remaining: from.Remaining,
+			reset:     from.reset,
+			limit:     from.limit,
+			window:    from.window,
+			global:    r.global,
+			limiter:   r,
+		}
+	}
+}
+
+func bucketKey(hash, route string) string {
+	return hash + ":" + majorParameter(route)
+}
+
+func majorParameter(route string) string {
+	path := strings.TrimPrefix(strings.SplitN(route, "?", 2)[0], EndpointAPI)
+	parts := strings.Split(path, "/")
+
+	switch parts[0] {
+	case "channels", "guilds":
+		if len(parts) > 1 {
+			return parts[0] + "/" + parts[1]
+		}
+	case "webhooks":
+		if len(parts) > 2 {
+			return parts[0] + "/" + parts[1] + "/" + parts[2]
+		}
+		if len(parts) > 1 {
+			return parts[0] + "/" + parts[1]
+		}
+	}
+
+	return ""
+}
+
 func (r *RateLimiter) GetWaitTime(b *Bucket, minRemaining int) time.Duration {
 	if b.Remaining < minRemaining && b.reset.After(time.Now()) {
 		return time.Until(b.reset)
@@ -77,18 +145,68 @@ func (r *RateLimiter) GetWaitTime(b *Bucket, minRemaining int) time.Duration {
 }
 
 func (r *RateLimiter) LockBucket(bucketID string) *Bucket {
-	return r.LockBucketObject(r.GetBucket(bucketID))
+	b, _ := r.LockBucketContext(context.Background(), bucketID, 0)
+	return b
 }
 
 func (r *RateLimiter) LockBucketObject(b *Bucket) *Bucket {
-	b.Lock()
+	b, _ = r.LockBucketObjectContext(context.Background(), b, 0)
+	return b
+}
+
+func (r *RateLimiter) LockBucketContext(ctx context.Context, bucketID string, maxWait time.Duration) (*Bucket, error) {
+	b, err := r.LockBucketObjectContext(ctx, r.GetBucket(bucketID), maxWait)
+	if err != nil {
+		return nil, err
+	}
+
+	b.route = bucketID
+	return b, nil
+}
+
+func (r *RateLimiter) LockBucketObjectContext(ctx context.Context, b *Bucket, maxWait time.Duration) (*Bucket, error) {
+	if err := b.LockContext(ctx); err != nil {
+		return nil, err
+	}
 
+	b.refill(time.Now())
 	if wait := r.GetWaitTime(b, 1); wait > 0 {
-		time.Sleep(wait)
+		if err := waitRateLimit(ctx, wait, maxWait); err != nil {
+			b.Unlock()
+			return nil, err
+		}
 	}
 
 	b.Remaining--
-	return b
+	return b, nil
+}
+
+func (r *RateLimiter) Release(b *Bucket, headers http.Header) error {
+	return b.Release(headers)
+}
+
+func waitRateLimit(ctx context.Context, wait, maxWait time.Duration) error {
+	if maxWait > 0 && wait > maxWait {
+		return fmt.Errorf("%w: %s", ErrRateLimitWaitExceeded, wait)
+	}
+
+	return sleepContext(ctx, wait)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 type Bucket struct {
@@ -96,15 +214,54 @@ type Bucket struct {
 	Key             string
 	Remaining       int
 	reset           time.Time
+	limit           int
+	window          time.Duration
 	global          *int64
 	lastReset       time.Time
 	customRateLimit *customRateLimit
+	limiter         *RateLimiter
+	route           string
 	Userdata        interface{}
 }
 
+func (b *Bucket) LockContext(ctx context.Context) error {
+	if ctx.Done() == nil {
+		b.Lock()
+		return nil
+	}
+
+	locked := make(chan struct{})
+	go func() {
+		b.Lock()
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+		return nil
+	case <-ctx.Done():
+		go func() {
+			<-locked
+			b.Unlock()
+		}()
+		return ctx.Err()
+	}
+}
+
 func (b *Bucket) Release(headers http.Header) error {
 	defer b.Unlock()
 
+	return b.update(headers)
+}
+
+func (b *Bucket) refill(now time.Time) {
+	if b.limit > 0 && b.window > 0 && !b.reset.IsZero() && !now.Before(b.reset) {
+		b.Remaining = b.limit
+		b.reset = now.Add(b.window)
+	}
+}
+
+func (b *Bucket) update(headers http.Header) error {
 	if rl := b.customRateLimit; rl != nil {
 		if time.Since(b.lastReset) >= rl.reset {
 			b.Remaining = rl.requests - 1
@@ -120,6 +277,7 @@ func (b *Bucket) Release(headers http.Header) error {
 		return nil
 	}
 
+	limit := headers.Get("X-RateLimit-Limit")
 	remaining := headers.Get("X-RateLimit-Remaining")
 	reset := headers.Get("X-RateLimit-Reset")
 	global := headers.Get("X-RateLimit-Global")
@@ -130,11 +288,17 @@ func (b *Bucket) Release(headers http.Header) error {
 		if err != nil {
 			return fmt.Errorf("invalid X-RateLimit-Reset-After: %w", err)
 		}
-		resetAt := time.Now().Add(time.Duration(parsedAfter * float64(time.Second)))
+		window := time.Duration(parsedAfter * float64(time.Second))
+		resetAt := time.Now().Add(window)
 		if global != "" {
-			atomic.StoreInt64(b.global, resetAt.UnixNano())
+			if b.global != nil {
+				atomic.StoreInt64(b.global, resetAt.UnixNano())
+			}
 		} else {
 			b.reset = resetAt
+			if window > b.window {
+				b.window = window
+			}
 		}
 	} else if reset != "" {
 		discordTime, err := http.ParseTime(headers.Get("Date"))
@@ -151,6 +315,14 @@ func (b *Bucket) Release(headers http.Header) error {
 		b.reset = time.Now().Add(delta)
 	}
 
+	if limit != "" {
+		limit, err := strconv.Atoi(limit)
+		if err != nil {
+			return fmt.Errorf("invalid X-RateLimit-Limit: %w", err)
+		}
+		b.limit = limit
+	}
+
 	if remaining != "" {
 		remaining, err := strconv.Atoi(remaining)
 		if err != nil {
@@ -159,5 +331,102 @@ func (b *Bucket) Release(headers http.Header) error {
 		b.Remaining = remaining
 	}
 
+	if hash := headers.Get("X-RateLimit-Bucket"); hash != "" && b.limiter != nil && b.route != "" {
+		b.limiter.learnRoute(b.route, hash, b)
+	}
+
+	return nil
+}
+
+type GatewayRateLimiter struct {
+	sync.Mutex
+	Limit     int
+	Interval  time.Duration
+	Reserved  int
+	Queue     bool
+	remaining int
+	reset     time.Time
+}
+
+func NewGatewayRateLimiter() *GatewayRateLimiter {
+	return &GatewayRateLimiter{
+		Limit:    120,
+		Interval: 60 * time.Second,
+		Reserved: 5,
+		Queue:    true,
+	}
+}
+
+func (l *GatewayRateLimiter) Reset() error {
+	l.Lock()
+	defer l.Unlock()
+
+	if err := l.validate(); err != nil {
+		return err
+	}
+
+	l.remaining = l.Limit
+	l.reset = time.Now().Add(l.Interval)
 	return nil
 }
+
+func (l *GatewayRateLimiter) validate() error {
+	if l.Limit < 1 || l.Interval <= 0 || l.Reserved < 0 || l.Reserved >= l.Limit {
+		return fmt.Errorf("%w: limit %d, reserved %d, interval %s", ErrGatewayRateLimiterConfig, l.Limit, l.Reserved, l.Interval)
+	}
+
+	return nil
+}
+
+func (l *GatewayRateLimiter) Remaining() int {
+	l.Lock()
+	defer l.Unlock()
+
+	l.refill()
+	return l.remaining
+}
+
+func (l *GatewayRateLimiter) refill() {
+	if now := time.Now(); !now.Before(l.reset) {
+		l.remaining = l.Limit
+		l.reset = now.Add(l.Interval)
+	}
+}
+
+func (l *GatewayRateLimiter) Wait(priority bool) error {
+	return l.WaitContext(context.Background(), priority)
+}
+
+func (l *GatewayRateLimiter) WaitContext(ctx context.Context, priority bool) error {
+	for {
+		l.Lock()
+		if err := l.validate(); err != nil {
+			l.Unlock()
+			return err
+		}
+		l.refill()
+
+		floor := l.Reserved
+		if priority {
+			floor = 0
+		}
+
+		if l.remaining > floor {
+			l.remaining--
+			l.Unlock()
+			return nil
+		}
+
+		wait := time.Until(l.reset)
+		queue := l.Queue
+		l.Unlock()
+
+		if !queue {
+			return ErrGatewayRateLimited
+		}
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
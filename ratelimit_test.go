@@ -0,0 +1,118 @@
+package discordgo
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func rateLimitHeaders(hash string, limit, remaining int, resetAfter string) http.Header {
+	h := http.Header{}
+	if hash != "" {
+		h.Set("X-RateLimit-Bucket", hash)
+	}
+	h.Set("X-RateLimit-Limit", strconv.Itoa(limit))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	h.Set("X-RateLimit-Reset-After", resetAfter)
+	return h
+}
+
+func TestRateLimiterLearnsBucketHash(t *testing.T) {
+	r := NewRatelimiter()
+
+	messages := EndpointChannelMessages("1")
+	message := EndpointChannelMessage("1", "")
+	other := EndpointChannelMessages("2")
+
+	b := r.LockBucket(messages)
+	if b.Key != messages {
+		t.Fatalf("got key %q before learning, want %q", b.Key, messages)
+	}
+	if err := r.Release(b, rateLimitHeaders("abc", 5, 0, "30")); err != nil {
+		t.Fatal(err)
+	}
+
+	learned := r.GetBucket(messages)
+	if learned.Key != "abc:channels/1" {
+		t.Fatalf("got key %q after learning, want %q", learned.Key, "abc:channels/1")
+	}
+	if wait := r.GetWaitTime(learned, 1); wait <= 0 {
+		t.Fatal("learned bucket did not inherit the exhausted state")
+	}
+
+	b = r.LockBucket(message)
+	if err := r.Release(b, rateLimitHeaders("abc", 5, 4, "30")); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.GetBucket(message); got != learned {
+		t.Fatalf("route sharing a hash and major parameter got bucket %q, want %q", got.Key, learned.Key)
+	}
+
+	b = r.LockBucket(other)
+	if err := r.Release(b, rateLimitHeaders("abc", 5, 4, "30")); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.GetBucket(other); got == learned || got.Key != "abc:channels/2" {
+		t.Fatalf("route with another major parameter got bucket %q", got.Key)
+	}
+}
+
+func TestMajorParameter(t *testing.T) {
+	tests := map[string]string{
+		EndpointChannelMessages("1"):               "channels/1",
+		EndpointGuildMembers("2") + "?limit=1":     "guilds/2",
+		EndpointWebhookToken("3", "token"):         "webhooks/3/token",
+		EndpointWebhook("3"):                       "webhooks/3",
+		EndpointUser("4"):                          "",
+		EndpointApplicationGlobalCommands("appid"): "",
+	}
+
+	for route, want := range tests {
+		if got := majorParameter(route); got != want {
+			t.Errorf("majorParameter(%q) = %q, want %q", route, got, want)
+		}
+	}
+}
+
+func TestRateLimiterRefillsAfterReset(t *testing.T) {
+	r := NewRatelimiter()
+
+	b := r.LockBucket("refill")
+	if err := r.Release(b, rateLimitHeaders("", 2, 0, "0.05")); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		b, err := r.LockBucketContext(context.Background(), "refill", 10*time.Millisecond)
+		if err != nil {
+			t.Fatalf("request %d after reset: %v", i, err)
+		}
+		b.Unlock()
+	}
+
+	if _, err := r.LockBucketContext(context.Background(), "refill", 10*time.Millisecond); err == nil {
+		t.Fatal("refilled bucket let more requests through than its limit")
+	}
+}
+
+func TestLockBucketContextCancel(t *testing.T) {
+	r := NewRatelimiter()
+
+	b := r.LockBucket("busy")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := r.LockBucketContext(ctx, "busy", 0); err != context.DeadlineExceeded {
+		t.Fatalf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	b.Unlock()
+
+	b = r.LockBucket("busy")
+	b.Unlock()
+}
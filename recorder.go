@@ -0,0 +1,86 @@
+package discordgo
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+type RecordedFrame struct {
+	Operation int             `json:"op"`
+	Sequence  int64           `json:"s"`
+	Type      string          `json:"t,omitempty"`
+	RawData   json.RawMessage `json:"d"`
+	Timestamp time.Time       `json:"ts"`
+}
+
+type Recorder interface {
+	Record(f *RecordedFrame) error
+}
+
+type JSONLRecorder struct {
+	sync.Mutex
+	w io.Writer
+}
+
+func NewJSONLRecorder(w io.Writer) *JSONLRecorder {
+	return &JSONLRecorder{w: w}
+}
+
+func (r *JSONLRecorder) Record(f *RecordedFrame) error {
+	b, err := Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	_, err = r.w.Write(append(b, '\n'))
+	return err
+}
+
+func (s *Session) record(e *Event) {
+	f := &RecordedFrame{
+		Operation: e.Operation,
+		Sequence:  e.Sequence,
+		Type:      e.Type,
+		RawData:   e.RawData,
+		Timestamp: time.Now().UTC(),
+	}
+
+	if err := s.Recorder.Record(f); err != nil {
+		s.log(LogWarning, "error recording gateway frame, %s", err)
+	}
+}
+
+func (s *Session) Replay(r io.Reader) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var f RecordedFrame
+			if uerr := Unmarshal(line, &f); uerr != nil {
+				return uerr
+			}
+
+			if f.Operation == 0 {
+				s.dispatchEvent(&Event{
+					Operation: f.Operation,
+					Sequence:  f.Sequence,
+					Type:      f.Type,
+					RawData:   f.RawData,
+				}, false, true)
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package discordgo
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestReplayKeepsLiveSessionState(t *testing.T) {
+	s, err := New("Bot token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.SetResumeState(&ResumeState{SessionID: "live", Sequence: 5, ResumeGatewayURL: "wss://live.test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	r := NewJSONLRecorder(&buf)
+	frames := []*RecordedFrame{
+		{Operation: 0, Sequence: 100, Type: "READY", RawData: []byte(`{"session_id":"recorded","resume_gateway_url":"wss://recorded.test"}`)},
+		{Operation: 11},
+		{Operation: 0, Sequence: 101, Type: "TYPING_START", RawData: []byte(`{"channel_id":"1"}`)},
+	}
+	for _, f := range frames {
+		if err := r.Record(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ready, typing int
+	s.AddHandler(func(s *Session, e *Ready) {
+		ready++
+	})
+	s.AddHandler(func(s *Session, e *TypingStart) {
+		if e.ChannelID == "1" {
+			typing++
+		}
+	})
+
+	if err := s.Replay(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if ready != 1 || typing != 1 {
+		t.Fatalf("replayed %d READY and %d TYPING_START events, want 1 of each", ready, typing)
+	}
+
+	rs := s.ResumeState()
+	if rs.SessionID != "live" || rs.Sequence != 5 || rs.ResumeGatewayURL != "wss://live.test" {
+		t.Fatalf("replay changed the live resume state: %+v", rs)
+	}
+
+	if s.SyncEvents {
+		t.Fatal("replay changed SyncEvents")
+	}
+}
+
+func TestReplayETFRecording(t *testing.T) {
+	recording, err := New("Bot token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	recording.Encoding = GatewayEncodingETF
+	recording.Recorder = NewJSONLRecorder(&buf)
+
+	frame, err := ETFMarshal(map[string]interface{}{
+		"op": 0,
+		"s":  int64(7),
+		"t":  "MESSAGE_CREATE",
+		"d": map[string]interface{}{
+			"id":         int64(1234567890123456789),
+			"channel_id": int64(987654321098765432),
+			"guild_id":   int64(876543210987654321),
+			"content":    "hi",
+			"author":     map[string]interface{}{"id": int64(112233445566778899), "username": "u"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := recording.onEvent(websocket.BinaryMessage, frame); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := New("Bot token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got *MessageCreate
+	s.AddHandler(func(s *Session, m *MessageCreate) {
+		got = m
+	})
+
+	if err := s.Replay(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if got == nil {
+		t.Fatal("replayed MESSAGE_CREATE was not handled")
+	}
+	if got.ID != "1234567890123456789" || got.ChannelID != "987654321098765432" || got.GuildID != "876543210987654321" || got.Author == nil || got.Author.ID != "112233445566778899" {
+		t.Fatalf("unexpected replayed message %+v", got.Message)
+	}
+}
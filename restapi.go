@@ -9,7 +9,9 @@ import (
 	_ "image/png"
 	"io"
 	"log"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
 	"strconv"
 	"strings"
@@ -43,6 +45,19 @@ func (r RESTError) Error() string {
 	return fmt.Sprintf("HTTP %s, %s", r.Response.Status, string(r.ResponseBody))
 }
 
+func (r RESTError) Is(target error) bool {
+	code, ok := target.(APIErrorCode)
+	return ok && r.Message != nil && r.Message.Code == code
+}
+
+func (r RESTError) FieldErrors() []*APIFieldError {
+	if r.Message == nil {
+		return nil
+	}
+
+	return r.Message.Errors.FieldErrors()
+}
+
 type RateLimitError struct {
 	*RateLimit
 }
@@ -55,6 +70,8 @@ type RequestConfig struct {
 	Request                *http.Request
 	ShouldRetryOnRateLimit bool
 	MaxRestRetries         int
+	MaxRateLimitWait       time.Duration
+	RetryPolicy            *RetryPolicy
 	Client                 *http.Client
 }
 
@@ -62,6 +79,8 @@ func newRequestConfig(s *Session, req *http.Request) *RequestConfig {
 	return &RequestConfig{
 		ShouldRetryOnRateLimit: s.ShouldRetryOnRateLimit,
 		MaxRestRetries:         s.MaxRestRetries,
+		MaxRateLimitWait:       s.MaxRateLimitWait,
+		RetryPolicy:            s.RetryPolicy,
 		Client:                 s.Client,
 		Request:                req,
 	}
@@ -89,6 +108,18 @@ func WithRestRetries(max int) RequestOption {
 	}
 }
 
+func WithMaxRateLimitWait(max time.Duration) RequestOption {
+	return func(cfg *RequestConfig) {
+		cfg.MaxRateLimitWait = max
+	}
+}
+
+func WithRetryPolicy(policy *RetryPolicy) RequestOption {
+	return func(cfg *RequestConfig) {
+		cfg.RetryPolicy = policy
+	}
+}
+
 func WithHeader(key, value string) RequestOption {
 	return func(cfg *RequestConfig) {
 		cfg.Request.Header.Set(key, value)
@@ -129,21 +160,26 @@ func (s *Session) request(method, urlStr, contentType string, b []byte, bucketID
 	if bucketID == "" {
 		bucketID = strings.SplitN(urlStr, "?", 2)[0]
 	}
-	return s.RequestWithLockedBucket(method, urlStr, contentType, b, s.Ratelimiter.LockBucket(bucketID), sequence, options...)
-}
 
-func (s *Session) RequestWithLockedBucket(method, urlStr, contentType string, b []byte, bucket *Bucket, sequence int, options ...RequestOption) (response []byte, err error) {
-	if s.Debug {
-		log.Printf("API Request %8s: %s\n", method, urlStr)
-		log.Printf("API Request Payload: [%s]\n", string(b))
+	cfg, err := s.newRequest(method, urlStr, contentType, b, options)
+	if err != nil {
+		return
 	}
 
-	req, err := http.NewRequest(method, urlStr, bytes.NewBuffer(b))
+	bucket, err := s.Ratelimiter.LockBucketContext(cfg.Request.Context(), bucketID, cfg.MaxRateLimitWait)
 	if err != nil {
-		bucket.Release(nil)
 		return
 	}
 
+	return s.requestWithLockedBucket(cfg, b, bucket, sequence)
+}
+
+func (s *Session) newRequest(method, urlStr, contentType string, b []byte, options []RequestOption) (*RequestConfig, error) {
+	req, err := http.NewRequest(method, urlStr, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
 	if s.Token != "" {
 		req.Header.Set("Authorization", s.Token)
 	}
@@ -158,7 +194,41 @@ func (s *Session) RequestWithLockedBucket(method, urlStr, contentType string, b
 	for _, opt := range options {
 		opt(cfg)
 	}
-	req = cfg.Request
+
+	return cfg, nil
+}
+
+func (s *Session) RequestWithLockedBucket(method, urlStr, contentType string, b []byte, bucket *Bucket, sequence int, options ...RequestOption) (response []byte, err error) {
+	cfg, err := s.newRequest(method, urlStr, contentType, b, options)
+	if err != nil {
+		s.Ratelimiter.Release(bucket, nil)
+		return
+	}
+
+	return s.requestWithLockedBucket(cfg, b, bucket, sequence)
+}
+
+func (s *Session) requestWithLockedBucket(cfg *RequestConfig, b []byte, bucket *Bucket, sequence int) (response []byte, err error) {
+	urlStr := cfg.Request.URL.String()
+	if s.Debug {
+		log.Printf("API Request %8s: %s\n", cfg.Request.Method, urlStr)
+		log.Printf("API Request Payload: [%s]\n", string(b))
+	}
+
+	err = s.checkInvalidRequests()
+	if err != nil {
+		s.Ratelimiter.Release(bucket, nil)
+		return
+	}
+
+	req := cfg.Request.Clone(cfg.Request.Context())
+	if cfg.Request.GetBody != nil {
+		req.Body, err = cfg.Request.GetBody()
+		if err != nil {
+			s.Ratelimiter.Release(bucket, nil)
+			return
+		}
+	}
 
 	if s.Debug {
 		for k, v := range req.Header {
@@ -168,7 +238,10 @@ func (s *Session) RequestWithLockedBucket(method, urlStr, contentType string, b
 
 	resp, err := cfg.Client.Do(req)
 	if err != nil {
-		bucket.Release(nil)
+		s.Ratelimiter.Release(bucket, nil)
+		if delay, ok := s.retryDelay(cfg, req, 0, err, sequence); ok {
+			return s.retryRequest(cfg, b, bucket, sequence, delay)
+		}
 		return
 	}
 	defer func() {
@@ -178,7 +251,9 @@ func (s *Session) RequestWithLockedBucket(method, urlStr, contentType string, b
 		}
 	}()
 
-	err = bucket.Release(resp.Header)
+	s.recordInvalidRequest(resp)
+
+	err = s.Ratelimiter.Release(bucket, resp.Header)
 	if err != nil {
 		return
 	}
@@ -196,16 +271,19 @@ func (s *Session) RequestWithLockedBucket(method, urlStr, contentType string, b
 		log.Printf("API Response Body: [%s]\n\n\n", response)
 	}
 
+	if delay, ok := s.retryDelay(cfg, req, resp.StatusCode, nil, sequence); ok {
+		return s.retryRequest(cfg, b, bucket, sequence, delay)
+	}
+
 	switch resp.StatusCode {
 	case http.StatusOK:
 	case http.StatusCreated:
 	case http.StatusNoContent:
 	case http.StatusBadGateway:
-		if sequence < cfg.MaxRestRetries {
-			s.log(LogInformational, "%s failed (%s), retrying...", urlStr, resp.Status)
-			response, err = s.RequestWithLockedBucket(method, urlStr, contentType, b, s.Ratelimiter.LockBucketObject(bucket), sequence+1, options...)
-		} else {
+		if sequence >= cfg.MaxRestRetries {
 			err = fmt.Errorf("exceeded retry limit: HTTP %s, %s", resp.Status, response)
+		} else {
+			err = newRestError(req, resp, response)
 		}
 	case 429:
 		rl := TooManyRequests{}
@@ -215,13 +293,20 @@ func (s *Session) RequestWithLockedBucket(method, urlStr, contentType string, b
 			return
 		}
 
-		if cfg.ShouldRetryOnRateLimit {
+		if cfg.ShouldRetryOnRateLimit && (cfg.MaxRateLimitWait <= 0 || rl.RetryAfter <= cfg.MaxRateLimitWait) {
 			s.log(LogInformational, "Rate Limiting %s, retrying in %v...", urlStr, rl.RetryAfter)
 			s.handleEvent(rateLimitEventType, &RateLimit{TooManyRequests: &rl, URL: urlStr})
 
-			time.Sleep(rl.RetryAfter)
+			err = waitRateLimit(req.Context(), rl.RetryAfter, 0)
+			if err != nil {
+				return
+			}
 
-			response, err = s.RequestWithLockedBucket(method, urlStr, contentType, b, s.Ratelimiter.LockBucketObject(bucket), sequence, options...)
+			bucket, err = s.Ratelimiter.LockBucketObjectContext(req.Context(), bucket, cfg.MaxRateLimitWait)
+			if err != nil {
+				return
+			}
+			response, err = s.requestWithLockedBucket(cfg, b, bucket, sequence)
 		} else {
 			err = &RateLimitError{&RateLimit{TooManyRequests: &rl, URL: urlStr}}
 		}
@@ -614,6 +699,53 @@ func (s *Session) GuildBanCreateWithReason(guildID, userID, reason string, days
 	return
 }
 
+type guildBulkBanData struct {
+	UserIDs              []string `json:"user_ids"`
+	DeleteMessageSeconds int      `json:"delete_message_seconds,omitempty"`
+}
+
+func (s *Session) GuildBulkBan(guildID string, userIDs []string, deleteMessageSeconds int, options ...RequestOption) (st *GuildBulkBanResponse, err error) {
+	if len(userIDs) == 0 || len(userIDs) > GuildBulkBanMaxUsers {
+		err = ErrBulkBanUsersBounds
+		return
+	}
+
+	data := guildBulkBanData{userIDs, deleteMessageSeconds}
+	body, err := s.RequestWithBucketID("POST", EndpointGuildBulkBan(guildID), data, EndpointGuildBulkBan(guildID), options...)
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+func (s *Session) GuildBulkBanChunked(guildID string, userIDs []string, deleteMessageSeconds int, options ...RequestOption) (st *GuildBulkBanResponse, err error) {
+	st = &GuildBulkBanResponse{
+		BannedUsers: []string{},
+		FailedUsers: []string{},
+	}
+
+	for start := 0; start < len(userIDs); start += GuildBulkBanMaxUsers {
+		end := start + GuildBulkBanMaxUsers
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+
+		var res *GuildBulkBanResponse
+		res, err = s.GuildBulkBan(guildID, userIDs[start:end], deleteMessageSeconds, options...)
+		if err != nil {
+			st.RemainingUsers = append([]string{}, userIDs[start:]...)
+			return
+		}
+
+		st.BannedUsers = append(st.BannedUsers, res.BannedUsers...)
+		st.FailedUsers = append(st.FailedUsers, res.FailedUsers...)
+	}
+
+	return
+}
+
 func (s *Session) GuildBanDelete(guildID, userID string, options ...RequestOption) (err error) {
 	_, err = s.RequestWithBucketID("DELETE", EndpointGuildBan(guildID, userID), nil, EndpointGuildBan(guildID, ""), options...)
 	return
@@ -1026,6 +1158,7 @@ func (s *Session) GuildSplash(guildID string, options ...RequestOption) (img ima
 	return
 }
 
+// Deprecated: Use GuildWidgetSettings instead.
 func (s *Session) GuildEmbed(guildID string, options ...RequestOption) (st *GuildEmbed, err error) {
 	body, err := s.RequestWithBucketID("GET", EndpointGuildEmbed(guildID), nil, EndpointGuildEmbed(guildID), options...)
 	if err != nil {
@@ -1036,11 +1169,72 @@ func (s *Session) GuildEmbed(guildID string, options ...RequestOption) (st *Guil
 	return
 }
 
+// Deprecated: Use GuildWidgetSettingsEdit instead.
 func (s *Session) GuildEmbedEdit(guildID string, data *GuildEmbed, options ...RequestOption) (err error) {
 	_, err = s.RequestWithBucketID("PATCH", EndpointGuildEmbed(guildID), data, EndpointGuildEmbed(guildID), options...)
 	return
 }
 
+func (s *Session) GuildWidgetSettings(guildID string, options ...RequestOption) (st *GuildWidgetSettings, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuildWidget(guildID), nil, EndpointGuildWidget(guildID), options...)
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+func (s *Session) GuildWidgetSettingsEdit(guildID string, data *GuildWidgetSettingsParams, options ...RequestOption) (st *GuildWidgetSettings, err error) {
+	body, err := s.RequestWithBucketID("PATCH", EndpointGuildWidget(guildID), data, EndpointGuildWidget(guildID), options...)
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+func (s *Session) GuildWidget(guildID string, options ...RequestOption) (st *GuildWidget, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuildWidgetJSON(guildID), nil, EndpointGuildWidgetJSON(guildID), options...)
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+func (s *Session) GuildVanityURL(guildID string, options ...RequestOption) (st *GuildVanityURL, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuildVanityURL(guildID), nil, EndpointGuildVanityURL(guildID), options...)
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+func (s *Session) GuildWelcomeScreen(guildID string, options ...RequestOption) (st *GuildWelcomeScreen, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuildWelcomeScreen(guildID), nil, EndpointGuildWelcomeScreen(guildID), options...)
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+func (s *Session) GuildWelcomeScreenEdit(guildID string, data *GuildWelcomeScreenParams, options ...RequestOption) (st *GuildWelcomeScreen, err error) {
+	body, err := s.RequestWithBucketID("PATCH", EndpointGuildWelcomeScreen(guildID), data, EndpointGuildWelcomeScreen(guildID), options...)
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
 func (s *Session) GuildAuditLog(guildID, userID, beforeID string, actionType, limit int, options ...RequestOption) (st *GuildAuditLog, err error) {
 	uri := EndpointGuildAuditLogs(guildID)
 
@@ -1116,6 +1310,201 @@ func (s *Session) GuildEmojiDelete(guildID, emojiID string, options ...RequestOp
 	return
 }
 
+func (s *Session) Sticker(stickerID string, options ...RequestOption) (st *Sticker, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointSticker(stickerID), nil, EndpointSticker(""), options...)
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+func (s *Session) StickerPacks(options ...RequestOption) (st []*StickerPack, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointNitroStickersPacks, nil, EndpointNitroStickersPacks, options...)
+	if err != nil {
+		return
+	}
+
+	var packs struct {
+		StickerPacks []*StickerPack `json:"sticker_packs"`
+	}
+
+	err = unmarshal(body, &packs)
+	st = packs.StickerPacks
+	return
+}
+
+func (s *Session) StickerPack(packID string, options ...RequestOption) (st *StickerPack, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointStickerPack(packID), nil, EndpointStickerPack(""), options...)
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+func (s *Session) GuildStickers(guildID string, options ...RequestOption) (st []*Sticker, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuildStickers(guildID), nil, EndpointGuildStickers(guildID), options...)
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+func (s *Session) GuildSticker(guildID, stickerID string, options ...RequestOption) (st *Sticker, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuildSticker(guildID, stickerID), nil, EndpointGuildStickers(guildID), options...)
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+func (s *Session) GuildStickerCreate(guildID string, data *StickerParams, options ...RequestOption) (st *Sticker, err error) {
+	if data == nil || data.File == nil {
+		err = ErrStickerFileMissing
+		return
+	}
+
+	contentType, b, err := stickerMultipartBody(data)
+	if err != nil {
+		return
+	}
+
+	endpoint := EndpointGuildStickers(guildID)
+	body, err := s.request("POST", endpoint, contentType, b, endpoint, 0, options...)
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+func stickerMultipartBody(data *StickerParams) (string, []byte, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	fields := [][2]string{
+		{"name", data.Name},
+		{"description", data.Description},
+		{"tags", data.Tags},
+	}
+	for _, field := range fields {
+		if err := writer.WriteField(field[0], field[1]); err != nil {
+			return "", nil, err
+		}
+	}
+
+	fileHeader := make(textproto.MIMEHeader)
+	fileHeader.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, quoteEscaper.Replace(data.File.Name)))
+
+	contentType := data.File.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	fileHeader.Set("Content-Type", contentType)
+
+	filePart, err := writer.CreatePart(fileHeader)
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err = io.Copy(filePart, data.File.Reader); err != nil {
+		return "", nil, err
+	}
+
+	if err = writer.Close(); err != nil {
+		return "", nil, err
+	}
+
+	return writer.FormDataContentType(), body.Bytes(), nil
+}
+
+func (s *Session) GuildStickerEdit(guildID, stickerID string, data *StickerParams, options ...RequestOption) (st *Sticker, err error) {
+	body, err := s.RequestWithBucketID("PATCH", EndpointGuildSticker(guildID, stickerID), data, EndpointGuildStickers(guildID), options...)
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+func (s *Session) GuildStickerDelete(guildID, stickerID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("DELETE", EndpointGuildSticker(guildID, stickerID), nil, EndpointGuildStickers(guildID), options...)
+	return
+}
+
+func (s *Session) SoundboardDefaultSounds(options ...RequestOption) (st []*SoundboardSound, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointSoundboardDefaultSounds, nil, EndpointSoundboardDefaultSounds, options...)
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+func (s *Session) GuildSoundboardSounds(guildID string, options ...RequestOption) (st []*SoundboardSound, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuildSoundboardSounds(guildID), nil, EndpointGuildSoundboardSounds(guildID), options...)
+	if err != nil {
+		return
+	}
+
+	var sounds struct {
+		Items []*SoundboardSound `json:"items"`
+	}
+
+	err = unmarshal(body, &sounds)
+	st = sounds.Items
+	return
+}
+
+func (s *Session) GuildSoundboardSound(guildID, soundID string, options ...RequestOption) (st *SoundboardSound, err error) {
+	body, err := s.RequestWithBucketID("GET", EndpointGuildSoundboardSound(guildID, soundID), nil, EndpointGuildSoundboardSounds(guildID), options...)
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+func (s *Session) GuildSoundboardSoundCreate(guildID string, data *SoundboardSoundParams, options ...RequestOption) (st *SoundboardSound, err error) {
+	body, err := s.RequestWithBucketID("POST", EndpointGuildSoundboardSounds(guildID), data, EndpointGuildSoundboardSounds(guildID), options...)
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+func (s *Session) GuildSoundboardSoundEdit(guildID, soundID string, data *SoundboardSoundParams, options ...RequestOption) (st *SoundboardSound, err error) {
+	body, err := s.RequestWithBucketID("PATCH", EndpointGuildSoundboardSound(guildID, soundID), data, EndpointGuildSoundboardSounds(guildID), options...)
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+func (s *Session) GuildSoundboardSoundDelete(guildID, soundID string, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("DELETE", EndpointGuildSoundboardSound(guildID, soundID), nil, EndpointGuildSoundboardSounds(guildID), options...)
+	return
+}
+
+func (s *Session) SendSoundboardSound(channelID string, data *SoundboardSoundSend, options ...RequestOption) (err error) {
+	_, err = s.RequestWithBucketID("POST", EndpointChannelSendSoundboardSound(channelID), data, EndpointChannelSendSoundboardSound(channelID), options...)
+	return
+}
+
 func (s *Session) ApplicationEmojis(appID string, options ...RequestOption) (emojis []*Emoji, err error) {
 	body, err := s.RequestWithBucketID("GET", EndpointApplicationEmojis(appID), nil, EndpointApplicationEmojis(appID), options...)
 	if err != nil {
@@ -2727,32 +3116,7 @@ func (s *Session) SKUs(appID string) (skus []*SKU, err error) {
 func (s *Session) Entitlements(appID string, filterOptions *EntitlementFilterOptions, options ...RequestOption) (entitlements []*Entitlement, err error) {
 	endpoint := EndpointEntitlements(appID)
 
-	queryParams := url.Values{}
-	if filterOptions != nil {
-		if filterOptions.UserID != "" {
-			queryParams.Set("user_id", filterOptions.UserID)
-		}
-		if len(filterOptions.SkuIDs) > 0 {
-			queryParams.Set("sku_ids", strings.Join(filterOptions.SkuIDs, ","))
-		}
-		if filterOptions.Before != nil {
-			queryParams.Set("before", filterOptions.Before.Format(time.RFC3339))
-		}
-		if filterOptions.After != nil {
-			queryParams.Set("after", filterOptions.After.Format(time.RFC3339))
-		}
-		if filterOptions.Limit > 0 {
-			queryParams.Set("limit", strconv.Itoa(filterOptions.Limit))
-		}
-		if filterOptions.GuildID != "" {
-			queryParams.Set("guild_id", filterOptions.GuildID)
-		}
-		if filterOptions.ExcludeEnded {
-			queryParams.Set("exclude_ended", "true")
-		}
-	}
-
-	body, err := s.RequestWithBucketID("GET", endpoint+"?"+queryParams.Encode(), nil, endpoint, options...)
+	body, err := s.RequestWithBucketID("GET", endpoint+"?"+entitlementsQuery(filterOptions).Encode(), nil, endpoint, options...)
 	if err != nil {
 		return
 	}
@@ -2761,6 +3125,37 @@ func (s *Session) Entitlements(appID string, filterOptions *EntitlementFilterOpt
 	return
 }
 
+func entitlementsQuery(filterOptions *EntitlementFilterOptions) url.Values {
+	queryParams := url.Values{}
+	if filterOptions == nil {
+		return queryParams
+	}
+
+	if filterOptions.UserID != "" {
+		queryParams.Set("user_id", filterOptions.UserID)
+	}
+	if len(filterOptions.SkuIDs) > 0 {
+		queryParams.Set("sku_ids", strings.Join(filterOptions.SkuIDs, ","))
+	}
+	if filterOptions.Before != nil {
+		queryParams.Set("before", filterOptions.Before.Format(time.RFC3339))
+	}
+	if filterOptions.After != nil {
+		queryParams.Set("after", filterOptions.After.Format(time.RFC3339))
+	}
+	if filterOptions.Limit > 0 {
+		queryParams.Set("limit", strconv.Itoa(filterOptions.Limit))
+	}
+	if filterOptions.GuildID != "" {
+		queryParams.Set("guild_id", filterOptions.GuildID)
+	}
+	if filterOptions.ExcludeEnded {
+		queryParams.Set("exclude_ended", "true")
+	}
+
+	return queryParams
+}
+
 func (s *Session) EntitlementConsume(appID, entitlementID string, options ...RequestOption) (err error) {
 	_, err = s.RequestWithBucketID("POST", EndpointEntitlementConsume(appID, entitlementID), nil, EndpointEntitlementConsume(appID, ""), options...)
 	return
@@ -2780,6 +3175,16 @@ func (s *Session) EntitlementTestDelete(appID, entitlementID string, options ...
 func (s *Session) Subscriptions(skuID string, userID string, before, after *time.Time, limit int, options ...RequestOption) (subscriptions []*Subscription, err error) {
 	endpoint := EndpointSubscriptions(skuID)
 
+	body, err := s.RequestWithBucketID("GET", endpoint+"?"+subscriptionsQuery(userID, before, after, limit).Encode(), nil, endpoint, options...)
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &subscriptions)
+	return
+}
+
+func subscriptionsQuery(userID string, before, after *time.Time, limit int) url.Values {
 	queryParams := url.Values{}
 	if before != nil {
 		queryParams.Set("before", before.Format(time.RFC3339))
@@ -2794,13 +3199,7 @@ func (s *Session) Subscriptions(skuID string, userID string, before, after *time
 		queryParams.Set("limit", strconv.Itoa(limit))
 	}
 
-	body, err := s.RequestWithBucketID("GET", endpoint+"?"+queryParams.Encode(), nil, endpoint, options...)
-	if err != nil {
-		return
-	}
-
-	err = unmarshal(body, &subscriptions)
-	return
+	return queryParams
 }
 
 func (s *Session) Subscription(skuID, subscriptionID, userID string, options ...RequestOption) (subscription *Subscription, err error) {
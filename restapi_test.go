@@ -0,0 +1,152 @@
+package discordgo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+func TestRESTErrorFieldErrors(t *testing.T) {
+	body := []byte(`{"code":50035,"message":"Invalid Form Body","errors":{"content":{"_errors":[{"code":"BASE_TYPE_MAX_LENGTH","message":"Must be 2000 or fewer in length."}]},"embeds":{"0":{"title":{"_errors":[{"code":"BASE_TYPE_REQUIRED","message":"This field is required"}]}}}}}`)
+	err := newRestError(nil, &http.Response{Status: "400 Bad Request"}, body)
+
+	if err.Message == nil {
+		t.Fatal("error message was not parsed")
+	}
+	if err.Message.Code != ErrCodeInvalidFormBody {
+		t.Fatalf("got code %d, want %d", err.Message.Code, ErrCodeInvalidFormBody)
+	}
+
+	fields := err.FieldErrors()
+	if len(fields) != 2 {
+		t.Fatalf("got %d field errors, want 2", len(fields))
+	}
+
+	want := []struct{ path, code string }{
+		{"content", "BASE_TYPE_MAX_LENGTH"},
+		{"embeds.0.title", "BASE_TYPE_REQUIRED"},
+	}
+	for i, w := range want {
+		if fields[i].Path != w.path || fields[i].Code != w.code {
+			t.Errorf("field error %d: got %s %s, want %s %s", i, fields[i].Path, fields[i].Code, w.path, w.code)
+		}
+	}
+}
+
+func TestRESTErrorUnknownErrorShapes(t *testing.T) {
+	tests := []string{
+		`{"code":50035,"message":"Invalid Form Body","errors":{"content":["bad"]}}`,
+		`{"code":50035,"message":"Invalid Form Body","errors":{"_errors":"bad","content":1}}`,
+		`{"code":50035,"message":"Invalid Form Body","errors":["bad"]}`,
+	}
+
+	for _, body := range tests {
+		err := newRestError(nil, &http.Response{Status: "400 Bad Request"}, []byte(body))
+		if err.Message == nil {
+			t.Fatalf("error message was not parsed from %s", body)
+		}
+		if err.Message.Code != ErrCodeInvalidFormBody || err.Message.Message != "Invalid Form Body" {
+			t.Fatalf("unexpected message %+v from %s", err.Message, body)
+		}
+		if fields := err.FieldErrors(); len(fields) != 0 {
+			t.Fatalf("got %d field errors from %s, want none", len(fields), body)
+		}
+	}
+}
+
+func TestRESTErrorIs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code":10003,"message":"Unknown Channel"}`))
+	}))
+	defer srv.Close()
+
+	s, err := New("Bot token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.RequestWithBucketID("GET", srv.URL+"/channels/1", nil, "channels/1")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if !errors.Is(err, ErrCodeUnknownChannel) {
+		t.Fatalf("errors.Is(%v, ErrCodeUnknownChannel) = false", err)
+	}
+	if errors.Is(err, ErrCodeUnknownGuild) {
+		t.Fatalf("errors.Is(%v, ErrCodeUnknownGuild) = true", err)
+	}
+
+	wrapped := fmt.Errorf("fetching channel: %w", err)
+	if !errors.Is(wrapped, ErrCodeUnknownChannel) {
+		t.Fatal("errors.Is does not see through wrapping")
+	}
+
+	var restErr *RESTError
+	if !errors.As(wrapped, &restErr) || restErr.Response.StatusCode != http.StatusNotFound {
+		t.Fatal("errors.As did not find the RESTError")
+	}
+}
+
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestGuildBulkBanChunkedReportsRemainingUsers(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls > 1 {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"code":50013,"message":"Missing Permissions"}`))
+			return
+		}
+
+		var data struct {
+			UserIDs []string `json:"user_ids"`
+		}
+		json.NewDecoder(r.Body).Decode(&data)
+		json.NewEncoder(w).Encode(GuildBulkBanResponse{BannedUsers: data.UserIDs[1:], FailedUsers: data.UserIDs[:1]})
+	}))
+	defer srv.Close()
+
+	target, _ := url.Parse(srv.URL)
+
+	s, err := New("Bot token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Client = &http.Client{Transport: rewriteTransport{target}}
+
+	userIDs := make([]string, GuildBulkBanMaxUsers+50)
+	for i := range userIDs {
+		userIDs[i] = strconv.Itoa(i + 1)
+	}
+
+	st, err := s.GuildBulkBanChunked("1", userIDs, 0)
+	if err == nil {
+		t.Fatal("expected the second chunk to fail")
+	}
+	if st == nil {
+		t.Fatal("expected a partial result alongside the error")
+	}
+
+	if len(st.BannedUsers) != GuildBulkBanMaxUsers-1 || len(st.FailedUsers) != 1 || st.FailedUsers[0] != "1" {
+		t.Fatalf("unexpected result for the first chunk: %d banned, failed %v", len(st.BannedUsers), st.FailedUsers)
+	}
+	if len(st.RemainingUsers) != 50 || st.RemainingUsers[0] != userIDs[GuildBulkBanMaxUsers] {
+		t.Fatalf("got %d remaining users, want 50", len(st.RemainingUsers))
+	}
+}
@@ -0,0 +1,73 @@
+package discordgo
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/gorilla/websocket"
+)
+
+type ResumeState struct {
+	SessionID        string `json:"session_id"`
+	Sequence         int64  `json:"seq"`
+	ResumeGatewayURL string `json:"resume_gateway_url"`
+	ShardID          int    `json:"shard_id"`
+	ShardCount       int    `json:"shard_count"`
+}
+
+func (s *Session) ResumeState() *ResumeState {
+	s.RLock()
+	defer s.RUnlock()
+
+	return &ResumeState{
+		SessionID:        s.sessionID,
+		Sequence:         atomic.LoadInt64(s.sequence),
+		ResumeGatewayURL: s.resumeGatewayURL,
+		ShardID:          s.ShardID,
+		ShardCount:       s.ShardCount,
+	}
+}
+
+func (s *Session) SetResumeState(rs *ResumeState) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.wsConn != nil {
+		return ErrWSAlreadyOpen
+	}
+
+	s.sessionID = rs.SessionID
+	s.resumeGatewayURL = rs.ResumeGatewayURL
+	atomic.StoreInt64(s.sequence, rs.Sequence)
+	if rs.ShardCount > 0 {
+		s.ShardID = rs.ShardID
+		s.ShardCount = rs.ShardCount
+	}
+
+	return nil
+}
+
+func (s *Session) OpenWithResumeState(ctx context.Context, rs *ResumeState) error {
+	if err := s.SetResumeState(rs); err != nil {
+		return err
+	}
+
+	return s.OpenContext(ctx)
+}
+
+func (s *Session) Handoff() (*ResumeState, error) {
+	s.stopReconnect()
+
+	s.RLock()
+	open := s.wsConn != nil
+	s.RUnlock()
+	if !open {
+		return nil, ErrWSNotFound
+	}
+
+	if err := s.closeWithCode(websocket.CloseServiceRestart); err != nil {
+		return nil, err
+	}
+
+	return s.ResumeState(), nil
+}
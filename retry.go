@@ -0,0 +1,133 @@
+package discordgo
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+type RetryPolicy struct {
+	StatusCodes          []int
+	AnyMethodStatusCodes []int
+	TransportErrors      bool
+	UnsafeMethods        bool
+	Backoff              BackoffPolicy
+}
+
+var DefaultRetryPolicy = &RetryPolicy{
+	StatusCodes: []int{
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+	AnyMethodStatusCodes: []int{
+		http.StatusBadGateway,
+	},
+	TransportErrors: true,
+	Backoff:         ExponentialBackoff{Base: 500 * time.Millisecond, Max: 10 * time.Second},
+}
+
+func (p *RetryPolicy) Retryable(method string, statusCode int, err error) bool {
+	if err == nil {
+		for _, code := range p.AnyMethodStatusCodes {
+			if code == statusCode {
+				return true
+			}
+		}
+	}
+
+	if !p.UnsafeMethods && !idempotentMethod(method) {
+		return false
+	}
+
+	if err != nil {
+		return p.TransportErrors && retryableTransportError(err)
+	}
+
+	for _, code := range p.StatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *RetryPolicy) Delay(attempt int) time.Duration {
+	if p.Backoff == nil {
+		return 0
+	}
+
+	return p.Backoff.Backoff(attempt)
+}
+
+func idempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+func retryableTransportError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+func (s *Session) retryDelay(cfg *RequestConfig, req *http.Request, statusCode int, err error, sequence int) (time.Duration, bool) {
+	p := cfg.RetryPolicy
+	if p == nil || sequence >= cfg.MaxRestRetries || req.Context().Err() != nil {
+		return 0, false
+	}
+
+	if !p.Retryable(req.Method, statusCode, err) {
+		return 0, false
+	}
+
+	delay := p.Delay(sequence + 1)
+	if err != nil {
+		s.log(LogInformational, "%s %s failed (%s), retrying in %v...", req.Method, req.URL, err, delay)
+	} else {
+		s.log(LogInformational, "%s %s failed (HTTP %d), retrying in %v...", req.Method, req.URL, statusCode, delay)
+	}
+
+	s.handleEvent(requestRetryEventType, &RequestRetry{
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		Attempt:    sequence + 1,
+		Delay:      delay,
+		StatusCode: statusCode,
+		Err:        err,
+	})
+
+	return delay, true
+}
+
+func (s *Session) retryRequest(cfg *RequestConfig, b []byte, bucket *Bucket, sequence int, delay time.Duration) (response []byte, err error) {
+	ctx := cfg.Request.Context()
+
+	err = sleepContext(ctx, delay)
+	if err != nil {
+		return
+	}
+
+	bucket, err = s.Ratelimiter.LockBucketObjectContext(ctx, bucket, cfg.MaxRateLimitWait)
+	if err != nil {
+		return
+	}
+
+	return s.requestWithLockedBucket(cfg, b, bucket, sequence+1)
+}
@@ -0,0 +1,26 @@
+package discordgo
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestDefaultRetryPolicyMethods(t *testing.T) {
+	p := DefaultRetryPolicy
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPatch} {
+		if !p.Retryable(method, http.StatusBadGateway, nil) {
+			t.Errorf("%s was not retried on HTTP 502", method)
+		}
+	}
+
+	if !p.Retryable(http.MethodGet, http.StatusServiceUnavailable, nil) {
+		t.Error("GET was not retried on HTTP 503")
+	}
+	if p.Retryable(http.MethodPost, http.StatusServiceUnavailable, nil) {
+		t.Error("POST was retried on HTTP 503")
+	}
+	if p.Retryable(http.MethodPost, 0, http.ErrHandlerTimeout) {
+		t.Error("POST was retried on a transport error")
+	}
+}
@@ -0,0 +1,530 @@
+package discordgo
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const identifyInterval = 5 * time.Second
+
+type ShardStatus struct {
+	ShardID          int
+	Connected        bool
+	Ready            bool
+	Guilds           int
+	Latency          time.Duration
+	LastHeartbeatAck time.Time
+}
+
+type ShardManager struct {
+	sync.RWMutex
+	Token           string
+	ShardCount      int
+	MaxConcurrency  int
+	Identify        Identify
+	LogLevel        int
+	StateEnabled    bool
+	SyncEvents      bool
+	Compression     GatewayCompression
+	Encoding        GatewayEncoding
+	Backoff         BackoffPolicy
+	Client          *http.Client
+	Ratelimiter     RESTRateLimiter
+	InvalidRequests *InvalidRequestBreaker
+	Shards          []*Session
+	rest            *Session
+	handlersMu      sync.RWMutex
+	handlers        map[string][]*eventHandlerInstance
+	onceHandlers    map[string][]*eventHandlerInstance
+	identifyMu      sync.Mutex
+	identifyAt      map[int]time.Time
+	concurrency     int
+}
+
+func NewShardManager(token string) (*ShardManager, error) {
+	rest, err := New(token)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &ShardManager{
+		Token:           token,
+		Identify:        rest.Identify,
+		StateEnabled:    true,
+		Encoding:        rest.Encoding,
+		Client:          rest.Client,
+		Ratelimiter:     rest.Ratelimiter,
+		InvalidRequests: rest.InvalidRequests,
+		rest:            rest,
+		identifyAt:      make(map[int]time.Time),
+	}
+
+	return m, nil
+}
+
+func (m *ShardManager) Open() error {
+	return m.OpenWithResumeStates(nil)
+}
+
+func (m *ShardManager) OpenWithResumeStates(states []*ResumeState) error {
+	shards, concurrency, err := m.prepareShards(states)
+	if err != nil {
+		return err
+	}
+
+	count := len(shards)
+	for start := 0; start < count; start += concurrency {
+		end := start + concurrency
+		if end > count {
+			end = count
+		}
+
+		errs := make(chan error, end-start)
+		for _, s := range shards[start:end] {
+			go func(s *Session) {
+				errs <- s.Open()
+			}(s)
+		}
+
+		for i := start; i < end; i++ {
+			if err := <-errs; err != nil {
+				m.rest.log(LogError, "error opening shard, %s", err)
+				m.closeOpening(shards)
+				return err
+			}
+		}
+
+		if !m.isOpening(shards) {
+			return ErrWSNotFound
+		}
+	}
+
+	return nil
+}
+
+func (m *ShardManager) prepareShards(states []*ResumeState) ([]*Session, int, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if len(m.Shards) > 0 {
+		return nil, 0, ErrWSAlreadyOpen
+	}
+
+	m.rest.Client = m.Client
+	m.rest.Ratelimiter = m.Ratelimiter
+	m.rest.InvalidRequests = m.InvalidRequests
+	m.rest.LogLevel = m.LogLevel
+
+	gb, err := m.rest.GatewayBot()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	count := m.ShardCount
+	if count < 1 && len(states) > 0 {
+		count = states[0].ShardCount
+	}
+	if count < 1 {
+		count = gb.Shards
+	}
+	if count < 1 {
+		count = 1
+	}
+
+	if len(states) == 0 && gb.SessionStartLimit.Total > 0 && gb.SessionStartLimit.Remaining < count {
+		return nil, 0, ErrShardStartLimit
+	}
+
+	concurrency := m.MaxConcurrency
+	if concurrency < 1 {
+		concurrency = gb.SessionStartLimit.MaxConcurrency
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	m.identifyMu.Lock()
+	m.concurrency = concurrency
+	m.identifyMu.Unlock()
+
+	shards := make([]*Session, count)
+	for i := range shards {
+		s, err := m.newShard(i, count)
+		if err != nil {
+			return nil, 0, err
+		}
+		shards[i] = s
+	}
+
+	for _, rs := range states {
+		if rs == nil || rs.ShardCount != count || rs.ShardID < 0 || rs.ShardID >= count {
+			continue
+		}
+		shards[rs.ShardID].SetResumeState(rs)
+	}
+
+	m.ShardCount = count
+	m.Shards = shards
+	return shards, concurrency, nil
+}
+
+func (m *ShardManager) isOpening(shards []*Session) bool {
+	m.RLock()
+	defer m.RUnlock()
+
+	return len(m.Shards) > 0 && m.Shards[0] == shards[0]
+}
+
+func (m *ShardManager) closeOpening(shards []*Session) {
+	m.Lock()
+	defer m.Unlock()
+
+	if len(m.Shards) > 0 && m.Shards[0] == shards[0] {
+		m.closeShards()
+	}
+}
+
+func (m *ShardManager) newShard(shardID, shardCount int) (*Session, error) {
+	s, err := New(m.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ShardID = shardID
+	s.ShardCount = shardCount
+	s.Identify = m.Identify
+	s.Identify.Shard = nil
+	s.LogLevel = m.LogLevel
+	s.StateEnabled = m.StateEnabled
+	s.SyncEvents = m.SyncEvents
+	s.Compression = m.Compression
+	s.Encoding = m.Encoding
+	s.ReconnectBackoff = m.Backoff
+	s.Client = m.Client
+	s.Ratelimiter = m.Ratelimiter
+	s.InvalidRequests = m.InvalidRequests
+	s.manager = m
+	return s, nil
+}
+
+func (m *ShardManager) identifyWait(ctx context.Context, shardID int) error {
+	m.identifyMu.Lock()
+	concurrency := m.concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	key := shardID % concurrency
+
+	now := time.Now()
+	wait := time.Until(m.identifyAt[key])
+	if wait < 0 {
+		wait = 0
+	}
+	m.identifyAt[key] = now.Add(wait + identifyInterval)
+	m.identifyMu.Unlock()
+
+	return sleepContext(ctx, wait)
+}
+
+func (m *ShardManager) Close() error {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.closeShards()
+}
+
+func (m *ShardManager) closeShards() (err error) {
+	for _, s := range m.Shards {
+		if s == nil {
+			continue
+		}
+		if cerr := s.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+
+	m.Shards = nil
+	return
+}
+
+func (m *ShardManager) Handoff() ([]*ResumeState, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	states := make([]*ResumeState, 0, len(m.Shards))
+	for _, s := range m.Shards {
+		rs, err := s.Handoff()
+		if err != nil {
+			return states, err
+		}
+		states = append(states, rs)
+	}
+
+	m.Shards = nil
+	return states, nil
+}
+
+func (m *ShardManager) Reopen(shardID int) error {
+	s := m.Shard(shardID)
+	if s == nil {
+		return ErrWSShardBounds
+	}
+
+	if err := s.Close(); err != nil {
+		s.log(LogWarning, "error closing shard %d, %s", shardID, err)
+	}
+
+	return s.Open()
+}
+
+func (m *ShardManager) Shard(shardID int) *Session {
+	m.RLock()
+	defer m.RUnlock()
+
+	if shardID < 0 || shardID >= len(m.Shards) {
+		return nil
+	}
+
+	return m.Shards[shardID]
+}
+
+func (m *ShardManager) ShardForGuild(guildID string) int {
+	m.RLock()
+	count := len(m.Shards)
+	m.RUnlock()
+
+	return shardForGuild(guildID, count)
+}
+
+func shardForGuild(guildID string, shardCount int) int {
+	if shardCount < 2 {
+		return 0
+	}
+
+	id, err := strconv.ParseUint(guildID, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return int((id >> 22) % uint64(shardCount))
+}
+
+func (m *ShardManager) SessionForGuild(guildID string) *Session {
+	return m.Shard(m.ShardForGuild(guildID))
+}
+
+func (m *ShardManager) Status() []ShardStatus {
+	m.RLock()
+	shards := make([]*Session, len(m.Shards))
+	copy(shards, m.Shards)
+	m.RUnlock()
+
+	status := make([]ShardStatus, len(shards))
+	for i, s := range shards {
+		s.RLock()
+		st := ShardStatus{
+			ShardID:          s.ShardID,
+			Connected:        s.wsConn != nil,
+			Ready:            s.DataReady,
+			Latency:          s.HeartbeatLatency(),
+			LastHeartbeatAck: s.LastHeartbeatAck,
+		}
+		s.RUnlock()
+
+		if s.State != nil {
+			s.State.RLock()
+			st.Guilds = len(s.State.Guilds)
+			s.State.RUnlock()
+		}
+
+		status[i] = st
+	}
+
+	return status
+}
+
+func (m *ShardManager) RequestGuildMembers(guildID, query string, limit int, nonce string, presences bool) error {
+	s := m.SessionForGuild(guildID)
+	if s == nil {
+		return ErrWSNotFound
+	}
+
+	return s.RequestGuildMembers(guildID, query, limit, nonce, presences)
+}
+
+func (m *ShardManager) RequestGuildMembersList(guildID string, userIDs []string, limit int, nonce string, presences bool) error {
+	s := m.SessionForGuild(guildID)
+	if s == nil {
+		return ErrWSNotFound
+	}
+
+	return s.RequestGuildMembersList(guildID, userIDs, limit, nonce, presences)
+}
+
+func (m *ShardManager) FetchGuildMembers(ctx context.Context, guildID, query string, userIDs []string, limit int, presences bool) (*GuildMembersResult, error) {
+	s := m.SessionForGuild(guildID)
+	if s == nil {
+		return nil, ErrWSNotFound
+	}
+
+	return s.FetchGuildMembers(ctx, guildID, query, userIDs, limit, presences)
+}
+
+func (m *ShardManager) RequestGuildMembersBatch(guildIDs []string, query string, limit int, nonce string, presences bool) error {
+	for shardID, ids := range m.groupGuilds(guildIDs) {
+		s := m.Shard(shardID)
+		if s == nil {
+			return ErrWSNotFound
+		}
+
+		if err := s.RequestGuildMembersBatch(ids, query, limit, nonce, presences); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *ShardManager) groupGuilds(guildIDs []string) map[int][]string {
+	groups := make(map[int][]string)
+	for _, id := range guildIDs {
+		shardID := m.ShardForGuild(id)
+		groups[shardID] = append(groups[shardID], id)
+	}
+
+	return groups
+}
+
+func (m *ShardManager) ChannelVoiceJoin(gID, cID string, mute, deaf bool) (*VoiceConnection, error) {
+	s := m.SessionForGuild(gID)
+	if s == nil {
+		return nil, ErrWSNotFound
+	}
+
+	return s.ChannelVoiceJoin(gID, cID, mute, deaf)
+}
+
+func (m *ShardManager) ChannelVoiceJoinManual(gID, cID string, mute, deaf bool) error {
+	s := m.SessionForGuild(gID)
+	if s == nil {
+		return ErrWSNotFound
+	}
+
+	return s.ChannelVoiceJoinManual(gID, cID, mute, deaf)
+}
+
+func (m *ShardManager) UpdateStatusComplex(usd UpdateStatusData) error {
+	m.RLock()
+	shards := make([]*Session, len(m.Shards))
+	copy(shards, m.Shards)
+	m.RUnlock()
+
+	for _, s := range shards {
+		if err := s.UpdateStatusComplex(usd); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *ShardManager) AddHandler(handler interface{}) func() {
+	eh := handlerForInterface(handler)
+
+	if eh == nil {
+		m.rest.log(LogError, "Invalid handler type, handler will never be called")
+		return func() {}
+	}
+
+	return m.addEventHandler(eh, false)
+}
+
+func (m *ShardManager) AddHandlerOnce(handler interface{}) func() {
+	eh := handlerForInterface(handler)
+
+	if eh == nil {
+		m.rest.log(LogError, "Invalid handler type, handler will never be called")
+		return func() {}
+	}
+
+	return m.addEventHandler(eh, true)
+}
+
+func (m *ShardManager) addEventHandler(eventHandler EventHandler, once bool) func() {
+	m.handlersMu.Lock()
+	defer m.handlersMu.Unlock()
+
+	if m.handlers == nil {
+		m.handlers = map[string][]*eventHandlerInstance{}
+	}
+	if m.onceHandlers == nil {
+		m.onceHandlers = map[string][]*eventHandlerInstance{}
+	}
+
+	ehi := &eventHandlerInstance{eventHandler}
+	if once {
+		m.onceHandlers[eventHandler.Type()] = append(m.onceHandlers[eventHandler.Type()], ehi)
+	} else {
+		m.handlers[eventHandler.Type()] = append(m.handlers[eventHandler.Type()], ehi)
+	}
+
+	return func() {
+		m.removeEventHandlerInstance(eventHandler.Type(), ehi)
+	}
+}
+
+func (m *ShardManager) removeEventHandlerInstance(t string, ehi *eventHandlerInstance) {
+	m.handlersMu.Lock()
+	defer m.handlersMu.Unlock()
+
+	handlers := m.handlers[t]
+	for i := range handlers {
+		if handlers[i] == ehi {
+			m.handlers[t] = append(handlers[:i], handlers[i+1:]...)
+		}
+	}
+
+	onceHandlers := m.onceHandlers[t]
+	for i := range onceHandlers {
+		if onceHandlers[i] == ehi {
+			m.onceHandlers[t] = append(onceHandlers[:i], onceHandlers[i+1:]...)
+		}
+	}
+}
+
+func (m *ShardManager) handlersFor(t string) []*eventHandlerInstance {
+	m.handlersMu.RLock()
+	handlers := append([]*eventHandlerInstance(nil), m.handlers[t]...)
+	once := len(m.onceHandlers[t]) > 0
+	m.handlersMu.RUnlock()
+
+	if once {
+		m.handlersMu.Lock()
+		handlers = append(handlers, m.onceHandlers[t]...)
+		m.onceHandlers[t] = nil
+		m.handlersMu.Unlock()
+	}
+
+	return handlers
+}
+
+func (m *ShardManager) hasEventHandlers() bool {
+	m.handlersMu.RLock()
+	defer m.handlersMu.RUnlock()
+
+	return len(m.handlers[eventEventType])+len(m.onceHandlers[eventEventType]) > 0
+}
+
+func (m *ShardManager) handleEvent(s *Session, t string, i interface{}, synchronous bool) {
+	handlers := append(m.handlersFor(interfaceEventType), m.handlersFor(t)...)
+	for _, eh := range handlers {
+		if synchronous {
+			eh.eventHandler.Handle(s, i)
+		} else {
+			go eh.eventHandler.Handle(s, i)
+		}
+	}
+}
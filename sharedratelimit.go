@@ -0,0 +1,501 @@
+package discordgo
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type coordinatorRequest struct {
+	ID      uint64      `json:"id"`
+	Op      string      `json:"op"`
+	Bucket  string      `json:"bucket"`
+	Headers http.Header `json:"headers,omitempty"`
+}
+
+type coordinatorResponse struct {
+	ID   uint64        `json:"id"`
+	Wait time.Duration `json:"wait"`
+}
+
+const coordinatorLeaseRetry = 50 * time.Millisecond
+
+type RateLimitCoordinator struct {
+	sync.Mutex
+	GlobalLimit    int
+	GlobalInterval time.Duration
+	LeaseTimeout   time.Duration
+	limiter        *RateLimiter
+	leases         map[string]time.Time
+	globalUsed     int
+	globalReset    time.Time
+	listeners      map[net.Listener]struct{}
+	conns          map[net.Conn]struct{}
+	closed         bool
+}
+
+func NewRateLimitCoordinator() *RateLimitCoordinator {
+	return &RateLimitCoordinator{
+		GlobalLimit:    50,
+		GlobalInterval: time.Second,
+		LeaseTimeout:   10 * time.Second,
+		limiter:        NewRatelimiter(),
+		leases:         make(map[string]time.Time),
+		listeners:      make(map[net.Listener]struct{}),
+		conns:          make(map[net.Conn]struct{}),
+	}
+}
+
+func (c *RateLimitCoordinator) ListenAndServe(network, address string) error {
+	l, err := net.Listen(network, address)
+	if err != nil {
+		return err
+	}
+
+	return c.Serve(l)
+}
+
+func (c *RateLimitCoordinator) Serve(l net.Listener) error {
+	c.Lock()
+	if c.closed {
+		c.Unlock()
+		l.Close()
+		return ErrCoordinatorClosed
+	}
+	c.listeners[l] = struct{}{}
+	c.Unlock()
+
+	defer func() {
+		c.Lock()
+		delete(c.listeners, l)
+		c.Unlock()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			c.Lock()
+			closed := c.closed
+			c.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+
+		go c.serveConn(conn)
+	}
+}
+
+func (c *RateLimitCoordinator) Close() error {
+	c.Lock()
+	defer c.Unlock()
+
+	c.closed = true
+	for l := range c.listeners {
+		l.Close()
+	}
+	for conn := range c.conns {
+		conn.Close()
+	}
+
+	return nil
+}
+
+func (c *RateLimitCoordinator) serveConn(conn net.Conn) {
+	c.Lock()
+	if c.closed {
+		c.Unlock()
+		conn.Close()
+		return
+	}
+	c.conns[conn] = struct{}{}
+	c.Unlock()
+
+	defer func() {
+		c.Lock()
+		delete(c.conns, conn)
+		c.Unlock()
+		conn.Close()
+	}()
+
+	var encMu sync.Mutex
+	dec := json.NewDecoder(conn)
+	enc := json.NewEncoder(conn)
+	for {
+		var req coordinatorRequest
+		if err := dec.Decode(&req); err != nil {
+			return
+		}
+
+		go func() {
+			resp := coordinatorResponse{ID: req.ID}
+			switch req.Op {
+			case "acquire":
+				resp.Wait = c.acquire(req.Bucket)
+			case "release":
+				c.releaseBucket(req.Bucket, req.Headers)
+			}
+
+			encMu.Lock()
+			err := enc.Encode(&resp)
+			encMu.Unlock()
+			if err != nil {
+				conn.Close()
+			}
+		}()
+	}
+}
+
+func (c *RateLimitCoordinator) acquire(bucketID string) time.Duration {
+	b := c.limiter.GetBucket(bucketID)
+	b.Lock()
+	defer b.Unlock()
+
+	now := time.Now()
+	unknown := b.limit == 0 && b.customRateLimit == nil
+	if unknown {
+		if wait := c.leaseWait(bucketID, now); wait > 0 {
+			return wait
+		}
+	}
+
+	b.refill(now)
+	wait := c.limiter.GetWaitTime(b, 1)
+	if wait == 0 {
+		wait = c.takeGlobal()
+	}
+	if wait == 0 {
+		b.Remaining--
+		if unknown {
+			c.Lock()
+			c.leases[bucketID] = now.Add(c.LeaseTimeout)
+			c.Unlock()
+		}
+	}
+
+	return wait
+}
+
+func (c *RateLimitCoordinator) leaseWait(bucketID string, now time.Time) time.Duration {
+	c.Lock()
+	defer c.Unlock()
+
+	expires, ok := c.leases[bucketID]
+	if !ok {
+		return 0
+	}
+
+	if !now.Before(expires) {
+		delete(c.leases, bucketID)
+		return 0
+	}
+
+	if wait := expires.Sub(now); wait < coordinatorLeaseRetry {
+		return wait
+	}
+	return coordinatorLeaseRetry
+}
+
+func (c *RateLimitCoordinator) takeGlobal() time.Duration {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.GlobalLimit <= 0 {
+		return 0
+	}
+
+	now := time.Now()
+	if !now.Before(c.globalReset) {
+		c.globalUsed = 0
+		c.globalReset = now.Add(c.GlobalInterval)
+	}
+
+	if c.globalUsed >= c.GlobalLimit {
+		return c.globalReset.Sub(now)
+	}
+
+	c.globalUsed++
+	return 0
+}
+
+func (c *RateLimitCoordinator) releaseBucket(bucketID string, headers http.Header) {
+	c.Lock()
+	delete(c.leases, bucketID)
+	c.Unlock()
+
+	b := c.limiter.GetBucket(bucketID)
+	b.Lock()
+	b.route = bucketID
+	b.Release(headers)
+}
+
+type SharedRateLimiter struct {
+	sync.Mutex
+	Network   string
+	Address   string
+	Timeout   time.Duration
+	Backoff   BackoffPolicy
+	local     *RateLimiter
+	conn      net.Conn
+	enc       *json.Encoder
+	encMu     sync.Mutex
+	nextID    uint64
+	pending   map[uint64]chan coordinatorResponse
+	dialing   bool
+	closed    bool
+	done      chan struct{}
+	bucketsMu sync.Mutex
+	buckets   map[string]*Bucket
+}
+
+func NewSharedRateLimiter(network, address string) *SharedRateLimiter {
+	return &SharedRateLimiter{
+		Network: network,
+		Address: address,
+		Timeout: 2 * time.Second,
+		Backoff: ExponentialBackoff{Base: 100 * time.Millisecond, Max: 30 * time.Second},
+		local:   NewRatelimiter(),
+		pending: make(map[uint64]chan coordinatorResponse),
+		done:    make(chan struct{}),
+		buckets: make(map[string]*Bucket),
+	}
+}
+
+func (l *SharedRateLimiter) LockBucket(bucketID string) *Bucket {
+	b, _ := l.LockBucketContext(context.Background(), bucketID, 0)
+	return b
+}
+
+func (l *SharedRateLimiter) LockBucketObject(b *Bucket) *Bucket {
+	b, _ = l.LockBucketObjectContext(context.Background(), b, 0)
+	return b
+}
+
+func (l *SharedRateLimiter) LockBucketContext(ctx context.Context, bucketID string, maxWait time.Duration) (*Bucket, error) {
+	return l.LockBucketObjectContext(ctx, l.bucket(bucketID), maxWait)
+}
+
+func (l *SharedRateLimiter) LockBucketObjectContext(ctx context.Context, b *Bucket, maxWait time.Duration) (*Bucket, error) {
+	if err := b.LockContext(ctx); err != nil {
+		return nil, err
+	}
+
+	wait, err := l.roundTrip(coordinatorRequest{Op: "acquire", Bucket: b.Key})
+	for err == nil && wait > 0 {
+		if err := waitRateLimit(ctx, wait, maxWait); err != nil {
+			b.Unlock()
+			return nil, err
+		}
+		wait, err = l.roundTrip(coordinatorRequest{Op: "acquire", Bucket: b.Key})
+	}
+
+	if err != nil {
+		b.refill(time.Now())
+		if wait := l.local.GetWaitTime(b, 1); wait > 0 {
+			if err := waitRateLimit(ctx, wait, maxWait); err != nil {
+				b.Unlock()
+				return nil, err
+			}
+		}
+	}
+
+	b.Remaining--
+	return b, nil
+}
+
+func (l *SharedRateLimiter) Release(b *Bucket, headers http.Header) error {
+	key := b.Key
+	err := b.Release(headers)
+
+	var forward http.Header
+	for k, v := range headers {
+		if strings.HasPrefix(k, "X-Ratelimit-") || k == "Date" {
+			if forward == nil {
+				forward = make(http.Header)
+			}
+			forward[k] = v
+		}
+	}
+
+	l.roundTrip(coordinatorRequest{Op: "release", Bucket: key, Headers: forward})
+	return err
+}
+
+func (l *SharedRateLimiter) bucket(bucketID string) *Bucket {
+	l.bucketsMu.Lock()
+	defer l.bucketsMu.Unlock()
+
+	if b, ok := l.buckets[bucketID]; ok {
+		return b
+	}
+
+	b := l.local.GetBucket(bucketID)
+	l.buckets[bucketID] = b
+	return b
+}
+
+func (l *SharedRateLimiter) roundTrip(req coordinatorRequest) (time.Duration, error) {
+	l.Lock()
+	if l.closed {
+		l.Unlock()
+		return 0, ErrCoordinatorClosed
+	}
+
+	if l.conn == nil {
+		l.reconnect()
+		l.Unlock()
+		return 0, ErrCoordinatorUnavailable
+	}
+
+	l.nextID++
+	req.ID = l.nextID
+	resp := make(chan coordinatorResponse, 1)
+	l.pending[req.ID] = resp
+	conn, enc := l.conn, l.enc
+	l.Unlock()
+
+	l.encMu.Lock()
+	if l.Timeout > 0 {
+		conn.SetWriteDeadline(time.Now().Add(l.Timeout))
+	}
+	err := enc.Encode(&req)
+	l.encMu.Unlock()
+	if err != nil {
+		l.drop(conn)
+		return 0, err
+	}
+
+	var timeout <-chan time.Time
+	if l.Timeout > 0 {
+		timer := time.NewTimer(l.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case r, ok := <-resp:
+		if !ok {
+			return 0, ErrCoordinatorUnavailable
+		}
+		return r.Wait, nil
+	case <-timeout:
+		l.drop(conn)
+		return 0, ErrCoordinatorUnavailable
+	case <-l.done:
+		return 0, ErrCoordinatorClosed
+	}
+}
+
+func (l *SharedRateLimiter) read(conn net.Conn) {
+	dec := json.NewDecoder(conn)
+	for {
+		var resp coordinatorResponse
+		if err := dec.Decode(&resp); err != nil {
+			l.drop(conn)
+			return
+		}
+
+		l.Lock()
+		ch, ok := l.pending[resp.ID]
+		delete(l.pending, resp.ID)
+		l.Unlock()
+
+		if ok {
+			ch <- resp
+		}
+	}
+}
+
+func (l *SharedRateLimiter) drop(conn net.Conn) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.conn != conn {
+		return
+	}
+
+	conn.Close()
+	l.conn = nil
+	l.failPending()
+	l.reconnect()
+}
+
+func (l *SharedRateLimiter) failPending() {
+	for id, ch := range l.pending {
+		close(ch)
+		delete(l.pending, id)
+	}
+}
+
+func (l *SharedRateLimiter) reconnect() {
+	if l.dialing || l.closed {
+		return
+	}
+
+	l.dialing = true
+	go l.dial()
+}
+
+func (l *SharedRateLimiter) dial() {
+	for attempt := 1; ; attempt++ {
+		conn, err := net.DialTimeout(l.Network, l.Address, l.Timeout)
+
+		l.Lock()
+		if l.closed {
+			l.dialing = false
+			l.Unlock()
+			if conn != nil {
+				conn.Close()
+			}
+			return
+		}
+
+		if err == nil {
+			l.conn = conn
+			l.enc = json.NewEncoder(conn)
+			l.dialing = false
+			l.Unlock()
+			go l.read(conn)
+			return
+		}
+
+		backoff := l.Backoff
+		l.Unlock()
+
+		var wait time.Duration
+		if backoff != nil {
+			wait = backoff.Backoff(attempt)
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-l.done:
+		}
+	}
+}
+
+func (l *SharedRateLimiter) Close() error {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.closed {
+		return nil
+	}
+
+	l.closed = true
+	close(l.done)
+	l.failPending()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	err := l.conn.Close()
+	l.conn = nil
+	return err
+}
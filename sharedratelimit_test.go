@@ -0,0 +1,181 @@
+package discordgo
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimitCoordinatorRefillsToLimit(t *testing.T) {
+	c := NewRateLimitCoordinator()
+
+	c.releaseBucket("bucket", rateLimitHeaders("", 2, 0, "0.05"))
+	time.Sleep(60 * time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		if wait := c.acquire("bucket"); wait != 0 {
+			t.Fatalf("acquire %d after reset waited %s", i, wait)
+		}
+	}
+
+	if wait := c.acquire("bucket"); wait <= 0 {
+		t.Fatal("coordinator let more acquires through than the bucket limit")
+	}
+}
+
+func TestSharedRateLimiterFallsBackWithoutBlocking(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := l.Addr().String()
+	l.Close()
+
+	r := NewSharedRateLimiter("tcp", address)
+	r.Backoff = ConstantBackoff{Delay: 10 * time.Millisecond}
+	defer r.Close()
+
+	start := time.Now()
+	b, err := r.LockBucketContext(context.Background(), "bucket", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Release(b, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("falling back to the local limiter took %s", elapsed)
+	}
+}
+
+func TestSharedRateLimiterUsesCoordinator(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewRateLimitCoordinator()
+	go c.Serve(l)
+	defer c.Close()
+
+	r := NewSharedRateLimiter("tcp", l.Addr().String())
+	r.Backoff = ConstantBackoff{Delay: 10 * time.Millisecond}
+	defer r.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := r.roundTrip(coordinatorRequest{Op: "acquire", Bucket: "probe"}); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("shared rate limiter never connected to the coordinator")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	b, err := r.LockBucketContext(context.Background(), "bucket", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Release(b, rateLimitHeaders("", 1, 0, "30")); err != nil {
+		t.Fatal(err)
+	}
+
+	if wait := c.acquire("bucket"); wait <= 0 {
+		t.Fatal("release was not forwarded to the coordinator")
+	}
+}
+
+func TestRateLimitCoordinatorLeasesUnknownBuckets(t *testing.T) {
+	c := NewRateLimitCoordinator()
+
+	if wait := c.acquire("unknown"); wait != 0 {
+		t.Fatalf("first acquire on an unknown bucket waited %s", wait)
+	}
+	if wait := c.acquire("unknown"); wait <= 0 {
+		t.Fatal("second acquire on an unknown bucket was let through before headers came back")
+	}
+
+	c.releaseBucket("unknown", rateLimitHeaders("", 5, 4, "30"))
+
+	if wait := c.acquire("unknown"); wait != 0 {
+		t.Fatalf("acquire after headers came back waited %s", wait)
+	}
+	if wait := c.acquire("unknown"); wait != 0 {
+		t.Fatalf("known bucket with remaining requests waited %s", wait)
+	}
+}
+
+func TestSharedRateLimiterMultiplexesRequests(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		dec := json.NewDecoder(conn)
+		enc := json.NewEncoder(conn)
+
+		var first, second coordinatorRequest
+		if dec.Decode(&first) != nil || dec.Decode(&second) != nil {
+			return
+		}
+
+		wait := map[string]time.Duration{"slow": time.Second, "fast": time.Millisecond}
+		enc.Encode(coordinatorResponse{ID: second.ID, Wait: wait[second.Bucket]})
+		enc.Encode(coordinatorResponse{ID: first.ID, Wait: wait[first.Bucket]})
+	}()
+
+	r := NewSharedRateLimiter("tcp", l.Addr().String())
+	r.Backoff = ConstantBackoff{Delay: 10 * time.Millisecond}
+	defer r.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		r.Lock()
+		connected := r.conn != nil
+		r.reconnect()
+		r.Unlock()
+		if connected {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("shared rate limiter never connected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	var wg sync.WaitGroup
+	got := make(map[string]time.Duration)
+	var mu sync.Mutex
+	for _, bucket := range []string{"slow", "fast"} {
+		wg.Add(1)
+		go func(bucket string) {
+			defer wg.Done()
+			wait, err := r.roundTrip(coordinatorRequest{Op: "acquire", Bucket: bucket})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			got[bucket] = wait
+			mu.Unlock()
+		}(bucket)
+	}
+	wg.Wait()
+
+	if got["slow"] != time.Second || got["fast"] != time.Millisecond {
+		t.Fatalf("responses were not matched to their requests: %v", got)
+	}
+}
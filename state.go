@@ -19,6 +19,7 @@ type State struct {
 	TrackRoles         bool
 	TrackVoice         bool
 	TrackPresences     bool
+	TrackSoundboard    bool
 
 	guildMap   map[string]*Guild
 	channelMap map[string]*Channel
@@ -39,6 +40,7 @@ func NewState() *State {
 		TrackRoles:         true,
 		TrackVoice:         true,
 		TrackPresences:     true,
+		TrackSoundboard:    true,
 		guildMap:           make(map[string]*Guild),
 		channelMap:         make(map[string]*Channel),
 		memberMap:          make(map[string]map[string]*Member),
@@ -100,6 +102,9 @@ func (s *State) GuildAdd(guild *Guild) error {
 		if guild.VoiceStates == nil {
 			guild.VoiceStates = g.VoiceStates
 		}
+		if guild.SoundboardSounds == nil {
+			guild.SoundboardSounds = g.SoundboardSounds
+		}
 		*g = *guild
 		return nil
 	}
@@ -664,6 +669,88 @@ func (s *State) EmojisAdd(guildID string, emojis []*Emoji) error {
 	return nil
 }
 
+func (s *State) SoundboardSound(guildID, soundID string) (*SoundboardSound, error) {
+	if s == nil {
+		return nil, ErrNilState
+	}
+
+	guild, err := s.Guild(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	for _, sound := range guild.SoundboardSounds {
+		if sound.SoundID == soundID {
+			return sound, nil
+		}
+	}
+
+	return nil, ErrStateNotFound
+}
+
+func (s *State) SoundboardSoundAdd(guildID string, sound *SoundboardSound) error {
+	if s == nil {
+		return ErrNilState
+	}
+
+	guild, err := s.Guild(guildID)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	for i, existing := range guild.SoundboardSounds {
+		if existing.SoundID == sound.SoundID {
+			guild.SoundboardSounds[i] = sound
+			return nil
+		}
+	}
+
+	guild.SoundboardSounds = append(guild.SoundboardSounds, sound)
+	return nil
+}
+
+func (s *State) SoundboardSoundRemove(guildID, soundID string) error {
+	if s == nil {
+		return ErrNilState
+	}
+
+	guild, err := s.Guild(guildID)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	for i, sound := range guild.SoundboardSounds {
+		if sound.SoundID == soundID {
+			guild.SoundboardSounds = append(guild.SoundboardSounds[:i], guild.SoundboardSounds[i+1:]...)
+			return nil
+		}
+	}
+
+	return ErrStateNotFound
+}
+
+func (s *State) soundboardSoundsSet(guildID string, sounds []*SoundboardSound) error {
+	guild, err := s.Guild(guildID)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	guild.SoundboardSounds = sounds
+	return nil
+}
+
 func (s *State) MessageAdd(message *Message) error {
 	if s == nil {
 		return ErrNilState
@@ -826,11 +913,12 @@ func (s *State) onReady(se *Session, r *Ready) (err error) {
 
 	if !se.StateEnabled {
 		ready := Ready{
-			Version:     r.Version,
-			SessionID:   r.SessionID,
-			User:        r.User,
-			Shard:       r.Shard,
-			Application: r.Application,
+			Version:          r.Version,
+			SessionID:        r.SessionID,
+			ResumeGatewayURL: r.ResumeGatewayURL,
+			User:             r.User,
+			Shard:            r.Shard,
+			Application:      r.Application,
 		}
 
 		s.Ready = ready
@@ -951,6 +1039,26 @@ func (s *State) OnInterface(se *Session, i interface{}) (err error) {
 			defer s.Unlock()
 			guild.Emojis = t.Emojis
 		}
+	case *GuildSoundboardSoundCreate:
+		if s.TrackSoundboard {
+			err = s.SoundboardSoundAdd(t.GuildID, t.SoundboardSound)
+		}
+	case *GuildSoundboardSoundUpdate:
+		if s.TrackSoundboard {
+			err = s.SoundboardSoundAdd(t.GuildID, t.SoundboardSound)
+		}
+	case *GuildSoundboardSoundDelete:
+		if s.TrackSoundboard {
+			err = s.SoundboardSoundRemove(t.GuildID, t.SoundID)
+		}
+	case *GuildSoundboardSoundsUpdate:
+		if s.TrackSoundboard {
+			err = s.soundboardSoundsSet(t.GuildID, t.SoundboardSounds)
+		}
+	case *SoundboardSounds:
+		if s.TrackSoundboard {
+			err = s.soundboardSoundsSet(t.GuildID, t.SoundboardSounds)
+		}
 	case *ChannelCreate:
 		if s.TrackChannels {
 			err = s.ChannelAdd(t.Channel)
@@ -1,10 +1,12 @@
 package discordgo
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math"
 	"net/http"
+	"sort"
 	"sync"
 	"time"
 
@@ -74,35 +76,60 @@ type Session struct {
 	Debug                              bool
 	LogLevel                           int
 	ShouldReconnectOnError             bool
+	ReconnectBackoff                   BackoffPolicy
+	GuildsReadyTimeout                 time.Duration
 	ShouldReconnectVoiceOnSessionError bool
 	ShouldRetryOnRateLimit             bool
 	Identify                           Identify
 	Compress                           bool
+	Compression                        GatewayCompression
+	Encoding                           GatewayEncoding
 	ShardID                            int
 	ShardCount                         int
 	StateEnabled                       bool
 	SyncEvents                         bool
 	DataReady                          bool
 	MaxRestRetries                     int
+	MaxRateLimitWait                   time.Duration
+	RetryPolicy                        *RetryPolicy
+	InvalidRequests                    *InvalidRequestBreaker
 	VoiceReady                         bool
 	UDPReady                           bool
 	VoiceConnections                   map[string]*VoiceConnection
 	State                              *State
 	Client                             *http.Client
 	Dialer                             *websocket.Dialer
+	GatewayDialer                      GatewayDialer
+	Recorder                           Recorder
 	UserAgent                          string
 	LastHeartbeatAck                   time.Time
 	LastHeartbeatSent                  time.Time
-	Ratelimiter                        *RateLimiter
+	Ratelimiter                        RESTRateLimiter
+	GatewayRateLimiter                 *GatewayRateLimiter
 	handlersMu                         sync.RWMutex
 	handlers                           map[string][]*eventHandlerInstance
 	onceHandlers                       map[string][]*eventHandlerInstance
-	wsConn                             *websocket.Conn
+	wsConn                             GatewayConn
+	inflater                           *streamInflater
+	inflaterMu                         sync.Mutex
 	listening                          chan interface{}
 	sequence                           *int64
 	gateway                            string
 	sessionID                          string
+	resumeGatewayURL                   string
 	wsMutex                            sync.Mutex
+	reidentify                         *time.Timer
+	reconnectMu                        sync.Mutex
+	cancelReconnect                    context.CancelFunc
+	chunkMu                            sync.Mutex
+	chunkWaiters                       map[string]*memberChunkWaiter
+	guildsMu                           sync.Mutex
+	guildsGeneration                   int
+	guildsReadySent                    bool
+	guildsReadyTimer                   *time.Timer
+	pendingGuilds                      map[string]bool
+	unavailableGuilds                  map[string]bool
+	manager                            *ShardManager
 }
 
 type ApplicationIntegrationType uint
@@ -512,6 +539,37 @@ type StickerPack struct {
 	BannerAssetID  string     `json:"banner_asset_id"`
 }
 
+type StickerParams struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Tags        string `json:"tags,omitempty"`
+	File        *File  `json:"-"`
+}
+
+type SoundboardSound struct {
+	SoundID   string  `json:"sound_id"`
+	Name      string  `json:"name"`
+	Volume    float64 `json:"volume"`
+	EmojiID   string  `json:"emoji_id,omitempty"`
+	EmojiName string  `json:"emoji_name,omitempty"`
+	GuildID   string  `json:"guild_id,omitempty"`
+	Available bool    `json:"available"`
+	User      *User   `json:"user,omitempty"`
+}
+
+type SoundboardSoundParams struct {
+	Name      string   `json:"name,omitempty"`
+	Sound     string   `json:"sound,omitempty"`
+	Volume    *float64 `json:"volume,omitempty"`
+	EmojiID   *string  `json:"emoji_id,omitempty"`
+	EmojiName *string  `json:"emoji_name,omitempty"`
+}
+
+type SoundboardSoundSend struct {
+	SoundID       string `json:"sound_id"`
+	SourceGuildID string `json:"source_guild_id,omitempty"`
+}
+
 type VerificationLevel int
 
 const (
@@ -574,6 +632,7 @@ type Guild struct {
 	Roles                       []*Role                    `json:"roles"`
 	Emojis                      []*Emoji                   `json:"emojis"`
 	Stickers                    []*Sticker                 `json:"stickers"`
+	SoundboardSounds            []*SoundboardSound         `json:"soundboard_sounds"`
 	Members                     []*Member                  `json:"members"`
 	Presences                   []*Presence                `json:"presences"`
 	MaxPresences                int                        `json:"max_presences"`
@@ -792,6 +851,14 @@ func (g *Guild) BannerURL(size string) string {
 	return bannerURL(g.Banner, EndpointGuildBanner(g.ID, g.Banner), EndpointGuildBannerAnimated(g.ID, g.Banner), size)
 }
 
+func (g *Guild) WidgetImageURL(style GuildWidgetStyle) string {
+	if style == "" {
+		return EndpointGuildWidgetImage(g.ID)
+	}
+
+	return EndpointGuildWidgetImage(g.ID) + "?style=" + string(style)
+}
+
 type UserGuild struct {
 	ID                       string         `json:"id"`
 	Name                     string         `json:"name"`
@@ -943,12 +1010,16 @@ type TimeStamps struct {
 }
 
 func (t *TimeStamps) UnmarshalJSON(data []byte) error {
+	return t.unmarshalFrom(jsonSource(data))
+}
+
+func (t *TimeStamps) unmarshalFrom(decode decodeFunc) error {
 	var aux struct {
 		End   float64 `json:"end,omitempty"`
 		Start float64 `json:"start,omitempty"`
 	}
 
-	if err := Unmarshal(data, &aux); err != nil {
+	if err := decode(&aux); err != nil {
 		return err
 	}
 
@@ -1079,6 +1150,14 @@ type GuildBan struct {
 	User   *User  `json:"user"`
 }
 
+const GuildBulkBanMaxUsers = 200
+
+type GuildBulkBanResponse struct {
+	BannedUsers    []string `json:"banned_users"`
+	FailedUsers    []string `json:"failed_users"`
+	RemainingUsers []string `json:"-"`
+}
+
 type AutoModerationRule struct {
 	ID              string                         `json:"id,omitempty"`
 	GuildID         string                         `json:"guild_id,omitempty"`
@@ -1143,11 +1222,79 @@ type AutoModerationAction struct {
 	Metadata *AutoModerationActionMetadata `json:"metadata,omitempty"`
 }
 
+// Deprecated: Use GuildWidgetSettings and GuildWidgetSettingsParams instead.
 type GuildEmbed struct {
 	Enabled   *bool  `json:"enabled,omitempty"`
 	ChannelID string `json:"channel_id,omitempty"`
 }
 
+type GuildWidgetSettings struct {
+	Enabled   bool   `json:"enabled"`
+	ChannelID string `json:"channel_id"`
+}
+
+type GuildWidgetSettingsParams struct {
+	Enabled   *bool   `json:"enabled,omitempty"`
+	ChannelID *string `json:"channel_id,omitempty"`
+}
+
+type GuildWidget struct {
+	ID            string                `json:"id"`
+	Name          string                `json:"name"`
+	InstantInvite string                `json:"instant_invite"`
+	Channels      []*GuildWidgetChannel `json:"channels"`
+	Members       []*GuildWidgetMember  `json:"members"`
+	PresenceCount int                   `json:"presence_count"`
+}
+
+type GuildWidgetChannel struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+}
+
+type GuildWidgetMember struct {
+	ID            string `json:"id"`
+	Username      string `json:"username"`
+	Discriminator string `json:"discriminator"`
+	Avatar        string `json:"avatar"`
+	Status        string `json:"status"`
+	AvatarURL     string `json:"avatar_url"`
+}
+
+type GuildWidgetStyle string
+
+const (
+	GuildWidgetStyleShield  GuildWidgetStyle = "shield"
+	GuildWidgetStyleBanner1 GuildWidgetStyle = "banner1"
+	GuildWidgetStyleBanner2 GuildWidgetStyle = "banner2"
+	GuildWidgetStyleBanner3 GuildWidgetStyle = "banner3"
+	GuildWidgetStyleBanner4 GuildWidgetStyle = "banner4"
+)
+
+type GuildVanityURL struct {
+	Code string `json:"code"`
+	Uses int    `json:"uses"`
+}
+
+type GuildWelcomeScreen struct {
+	Description     string                 `json:"description"`
+	WelcomeChannels []*GuildWelcomeChannel `json:"welcome_channels"`
+}
+
+type GuildWelcomeChannel struct {
+	ChannelID   string `json:"channel_id"`
+	Description string `json:"description"`
+	EmojiID     string `json:"emoji_id,omitempty"`
+	EmojiName   string `json:"emoji_name,omitempty"`
+}
+
+type GuildWelcomeScreenParams struct {
+	Enabled         *bool                  `json:"enabled,omitempty"`
+	WelcomeChannels []*GuildWelcomeChannel `json:"welcome_channels,omitempty"`
+	Description     *string                `json:"description,omitempty"`
+}
+
 type GuildAuditLog struct {
 	Webhooks        []*Webhook       `json:"webhooks,omitempty"`
 	Users           []*User          `json:"users,omitempty"`
@@ -1390,10 +1537,92 @@ type GuildMemberAddParams struct {
 }
 
 type APIErrorMessage struct {
-	Code    int    `json:"code"`
+	Code    APIErrorCode  `json:"code"`
+	Message string        `json:"message"`
+	Errors  *APIErrorTree `json:"errors,omitempty"`
+}
+
+type APIErrorCode int
+
+func (c APIErrorCode) Error() string {
+	return fmt.Sprintf("discord api error code %d", int(c))
+}
+
+type APIFieldError struct {
+	Path    string `json:"-"`
+	Code    string `json:"code"`
 	Message string `json:"message"`
 }
 
+func (e APIFieldError) Error() string {
+	return e.Path + ": " + e.Code
+}
+
+type APIErrorTree struct {
+	Errors   []*APIFieldError
+	Children map[string]*APIErrorTree
+}
+
+func (t *APIErrorTree) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if Unmarshal(data, &raw) != nil {
+		return nil
+	}
+
+	for key, value := range raw {
+		if key == "_errors" {
+			var errs []*APIFieldError
+			if Unmarshal(value, &errs) == nil {
+				t.Errors = errs
+			}
+			continue
+		}
+
+		child := &APIErrorTree{}
+		if Unmarshal(value, child) != nil || (len(child.Errors) == 0 && len(child.Children) == 0) {
+			continue
+		}
+
+		if t.Children == nil {
+			t.Children = make(map[string]*APIErrorTree)
+		}
+		t.Children[key] = child
+	}
+
+	return nil
+}
+
+func (t *APIErrorTree) FieldErrors() []*APIFieldError {
+	var errs []*APIFieldError
+	t.collect("", &errs)
+	return errs
+}
+
+func (t *APIErrorTree) collect(path string, errs *[]*APIFieldError) {
+	if t == nil {
+		return
+	}
+
+	for _, e := range t.Errors {
+		e.Path = path
+		*errs = append(*errs, e)
+	}
+
+	keys := make([]string, 0, len(t.Children))
+	for key := range t.Children {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		child := path + "." + key
+		if path == "" {
+			child = key
+		}
+		t.Children[key].collect(child, errs)
+	}
+}
+
 type MessageReaction struct {
 	UserID    string `json:"user_id"`
 	MessageID string `json:"message_id"`
@@ -1441,6 +1670,10 @@ type Activity struct {
 }
 
 func (a *Activity) UnmarshalJSON(data []byte) error {
+	return a.unmarshalFrom(jsonSource(data))
+}
+
+func (a *Activity) unmarshalFrom(decode decodeFunc) error {
 	var aux struct {
 		Name          string       `json:"name"`
 		Type          ActivityType `json:"type"`
@@ -1459,7 +1692,7 @@ func (a *Activity) UnmarshalJSON(data []byte) error {
 		SyncID        string       `json:"sync_id,omitempty"`
 	}
 
-	if err := Unmarshal(data, &aux); err != nil {
+	if err := decode(&aux); err != nil {
 		return err
 	}
 
@@ -1778,157 +2011,157 @@ const (
 )
 
 const (
-	ErrCodeGeneralError                                                     = 0
-	ErrCodeUnknownAccount                                                   = 10001
-	ErrCodeUnknownApplication                                               = 10002
-	ErrCodeUnknownChannel                                                   = 10003
-	ErrCodeUnknownGuild                                                     = 10004
-	ErrCodeUnknownIntegration                                               = 10005
-	ErrCodeUnknownInvite                                                    = 10006
-	ErrCodeUnknownMember                                                    = 10007
-	ErrCodeUnknownMessage                                                   = 10008
-	ErrCodeUnknownOverwrite                                                 = 10009
-	ErrCodeUnknownProvider                                                  = 10010
-	ErrCodeUnknownRole                                                      = 10011
-	ErrCodeUnknownToken                                                     = 10012
-	ErrCodeUnknownUser                                                      = 10013
-	ErrCodeUnknownEmoji                                                     = 10014
-	ErrCodeUnknownWebhook                                                   = 10015
-	ErrCodeUnknownWebhookService                                            = 10016
-	ErrCodeUnknownSession                                                   = 10020
-	ErrCodeUnknownBan                                                       = 10026
-	ErrCodeUnknownSKU                                                       = 10027
-	ErrCodeUnknownStoreListing                                              = 10028
-	ErrCodeUnknownEntitlement                                               = 10029
-	ErrCodeUnknownBuild                                                     = 10030
-	ErrCodeUnknownLobby                                                     = 10031
-	ErrCodeUnknownBranch                                                    = 10032
-	ErrCodeUnknownStoreDirectoryLayout                                      = 10033
-	ErrCodeUnknownRedistributable                                           = 10036
-	ErrCodeUnknownGiftCode                                                  = 10038
-	ErrCodeUnknownStream                                                    = 10049
-	ErrCodeUnknownPremiumServerSubscribeCooldown                            = 10050
-	ErrCodeUnknownGuildTemplate                                             = 10057
-	ErrCodeUnknownDiscoveryCategory                                         = 10059
-	ErrCodeUnknownSticker                                                   = 10060
-	ErrCodeUnknownInteraction                                               = 10062
-	ErrCodeUnknownApplicationCommand                                        = 10063
-	ErrCodeUnknownApplicationCommandPermissions                             = 10066
-	ErrCodeUnknownStageInstance                                             = 10067
-	ErrCodeUnknownGuildMemberVerificationForm                               = 10068
-	ErrCodeUnknownGuildWelcomeScreen                                        = 10069
-	ErrCodeUnknownGuildScheduledEvent                                       = 10070
-	ErrCodeUnknownGuildScheduledEventUser                                   = 10071
-	ErrUnknownTag                                                           = 10087
-	ErrCodeBotsCannotUseEndpoint                                            = 20001
-	ErrCodeOnlyBotsCanUseEndpoint                                           = 20002
-	ErrCodeExplicitContentCannotBeSentToTheDesiredRecipients                = 20009
-	ErrCodeYouAreNotAuthorizedToPerformThisActionOnThisApplication          = 20012
-	ErrCodeThisActionCannotBePerformedDueToSlowmodeRateLimit                = 20016
-	ErrCodeOnlyTheOwnerOfThisAccountCanPerformThisAction                    = 20018
-	ErrCodeMessageCannotBeEditedDueToAnnouncementRateLimits                 = 20022
-	ErrCodeChannelHasHitWriteRateLimit                                      = 20028
-	ErrCodeTheWriteActionYouArePerformingOnTheServerHasHitTheWriteRateLimit = 20029
-	ErrCodeStageTopicContainsNotAllowedWordsForPublicStages                 = 20031
-	ErrCodeGuildPremiumSubscriptionLevelTooLow                              = 20035
-	ErrCodeMaximumGuildsReached                                             = 30001
-	ErrCodeMaximumPinsReached                                               = 30003
-	ErrCodeMaximumNumberOfRecipientsReached                                 = 30004
-	ErrCodeMaximumGuildRolesReached                                         = 30005
-	ErrCodeMaximumNumberOfWebhooksReached                                   = 30007
-	ErrCodeMaximumNumberOfEmojisReached                                     = 30008
-	ErrCodeTooManyReactions                                                 = 30010
-	ErrCodeMaximumNumberOfGuildChannelsReached                              = 30013
-	ErrCodeMaximumNumberOfAttachmentsInAMessageReached                      = 30015
-	ErrCodeMaximumNumberOfInvitesReached                                    = 30016
-	ErrCodeMaximumNumberOfAnimatedEmojisReached                             = 30018
-	ErrCodeMaximumNumberOfServerMembersReached                              = 30019
-	ErrCodeMaximumNumberOfGuildDiscoverySubcategoriesReached                = 30030
-	ErrCodeGuildAlreadyHasATemplate                                         = 30031
-	ErrCodeMaximumNumberOfThreadParticipantsReached                         = 30033
-	ErrCodeMaximumNumberOfBansForNonGuildMembersHaveBeenExceeded            = 30035
-	ErrCodeMaximumNumberOfBansFetchesHasBeenReached                         = 30037
-	ErrCodeMaximumNumberOfUncompletedGuildScheduledEventsReached            = 30038
-	ErrCodeMaximumNumberOfStickersReached                                   = 30039
-	ErrCodeMaximumNumberOfPruneRequestsHasBeenReached                       = 30040
-	ErrCodeMaximumNumberOfGuildWidgetSettingsUpdatesHasBeenReached          = 30042
-	ErrCodeMaximumNumberOfEditsToMessagesOlderThanOneHourReached            = 30046
-	ErrCodeMaximumNumberOfPinnedThreadsInForumChannelHasBeenReached         = 30047
-	ErrCodeMaximumNumberOfTagsInForumChannelHasBeenReached                  = 30048
-	ErrCodeUnauthorized                                                     = 40001
-	ErrCodeActionRequiredVerifiedAccount                                    = 40002
-	ErrCodeOpeningDirectMessagesTooFast                                     = 40003
-	ErrCodeSendMessagesHasBeenTemporarilyDisabled                           = 40004
-	ErrCodeRequestEntityTooLarge                                            = 40005
-	ErrCodeFeatureTemporarilyDisabledServerSide                             = 40006
-	ErrCodeUserIsBannedFromThisGuild                                        = 40007
-	ErrCodeTargetIsNotConnectedToVoice                                      = 40032
-	ErrCodeMessageAlreadyCrossposted                                        = 40033
-	ErrCodeAnApplicationWithThatNameAlreadyExists                           = 40041
-	ErrCodeInteractionHasAlreadyBeenAcknowledged                            = 40060
-	ErrCodeTagNamesMustBeUnique                                             = 40061
-	ErrCodeMissingAccess                                                    = 50001
-	ErrCodeInvalidAccountType                                               = 50002
-	ErrCodeCannotExecuteActionOnDMChannel                                   = 50003
-	ErrCodeEmbedDisabled                                                    = 50004
-	ErrCodeGuildWidgetDisabled                                              = 50004
-	ErrCodeCannotEditFromAnotherUser                                        = 50005
-	ErrCodeCannotSendEmptyMessage                                           = 50006
-	ErrCodeCannotSendMessagesToThisUser                                     = 50007
-	ErrCodeCannotSendMessagesInVoiceChannel                                 = 50008
-	ErrCodeChannelVerificationLevelTooHigh                                  = 50009
-	ErrCodeOAuth2ApplicationDoesNotHaveBot                                  = 50010
-	ErrCodeOAuth2ApplicationLimitReached                                    = 50011
-	ErrCodeInvalidOAuthState                                                = 50012
-	ErrCodeMissingPermissions                                               = 50013
-	ErrCodeInvalidAuthenticationToken                                       = 50014
-	ErrCodeTooFewOrTooManyMessagesToDelete                                  = 50016
-	ErrCodeCanOnlyPinMessageToOriginatingChannel                            = 50019
-	ErrCodeInviteCodeWasEitherInvalidOrTaken                                = 50020
-	ErrCodeCannotExecuteActionOnSystemMessage                               = 50021
-	ErrCodeCannotExecuteActionOnThisChannelType                             = 50024
-	ErrCodeInvalidOAuth2AccessTokenProvided                                 = 50025
-	ErrCodeMissingRequiredOAuth2Scope                                       = 50026
-	ErrCodeInvalidWebhookTokenProvided                                      = 50027
-	ErrCodeInvalidRole                                                      = 50028
-	ErrCodeInvalidRecipients                                                = 50033
-	ErrCodeMessageProvidedTooOldForBulkDelete                               = 50034
-	ErrCodeInvalidFormBody                                                  = 50035
-	ErrCodeInviteAcceptedToGuildApplicationsBotNotIn                        = 50036
-	ErrCodeInvalidAPIVersionProvided                                        = 50041
-	ErrCodeFileUploadedExceedsTheMaximumSize                                = 50045
-	ErrCodeInvalidFileUploaded                                              = 50046
-	ErrCodeInvalidGuild                                                     = 50055
-	ErrCodeInvalidMessageType                                               = 50068
-	ErrCodeCannotDeleteAChannelRequiredForCommunityGuilds                   = 50074
-	ErrCodeInvalidStickerSent                                               = 50081
-	ErrCodePerformedOperationOnArchivedThread                               = 50083
-	ErrCodeBeforeValueIsEarlierThanThreadCreationDate                       = 50085
-	ErrCodeCommunityServerChannelsMustBeTextChannels                        = 50086
-	ErrCodeThisServerIsNotAvailableInYourLocation                           = 50095
-	ErrCodeThisServerNeedsMonetizationEnabledInOrderToPerformThisAction     = 50097
-	ErrCodeThisServerNeedsMoreBoostsToPerformThisAction                     = 50101
-	ErrCodeTheRequestBodyContainsInvalidJSON                                = 50109
-	ErrCodeNoUsersWithDiscordTagExist                                       = 80004
-	ErrCodeReactionBlocked                                                  = 90001
-	ErrCodeAPIResourceIsCurrentlyOverloaded                                 = 130000
-	ErrCodeTheStageIsAlreadyOpen                                            = 150006
-	ErrCodeCannotReplyWithoutPermissionToReadMessageHistory                 = 160002
-	ErrCodeThreadAlreadyCreatedForThisMessage                               = 160004
-	ErrCodeThreadIsLocked                                                   = 160005
-	ErrCodeMaximumNumberOfActiveThreadsReached                              = 160006
-	ErrCodeMaximumNumberOfActiveAnnouncementThreadsReached                  = 160007
-	ErrCodeInvalidJSONForUploadedLottieFile                                 = 170001
-	ErrCodeUploadedLottiesCannotContainRasterizedImages                     = 170002
-	ErrCodeStickerMaximumFramerateExceeded                                  = 170003
-	ErrCodeStickerFrameCountExceedsMaximumOfOneThousandFrames               = 170004
-	ErrCodeLottieAnimationMaximumDimensionsExceeded                         = 170005
-	ErrCodeStickerFrameRateOutOfRange                                       = 170006
-	ErrCodeStickerAnimationDurationExceedsMaximumOfFiveSeconds              = 170007
-	ErrCodeCannotUpdateAFinishedEvent                                       = 180000
-	ErrCodeFailedToCreateStageNeededForStageEvent                           = 180002
-	ErrCodeCannotEnableOnboardingRequirementsAreNotMet                      = 350000
-	ErrCodeCannotUpdateOnboardingWhileBelowRequirements                     = 350001
+	ErrCodeGeneralError                                                     APIErrorCode = 0
+	ErrCodeUnknownAccount                                                   APIErrorCode = 10001
+	ErrCodeUnknownApplication                                               APIErrorCode = 10002
+	ErrCodeUnknownChannel                                                   APIErrorCode = 10003
+	ErrCodeUnknownGuild                                                     APIErrorCode = 10004
+	ErrCodeUnknownIntegration                                               APIErrorCode = 10005
+	ErrCodeUnknownInvite                                                    APIErrorCode = 10006
+	ErrCodeUnknownMember                                                    APIErrorCode = 10007
+	ErrCodeUnknownMessage                                                   APIErrorCode = 10008
+	ErrCodeUnknownOverwrite                                                 APIErrorCode = 10009
+	ErrCodeUnknownProvider                                                  APIErrorCode = 10010
+	ErrCodeUnknownRole                                                      APIErrorCode = 10011
+	ErrCodeUnknownToken                                                     APIErrorCode = 10012
+	ErrCodeUnknownUser                                                      APIErrorCode = 10013
+	ErrCodeUnknownEmoji                                                     APIErrorCode = 10014
+	ErrCodeUnknownWebhook                                                   APIErrorCode = 10015
+	ErrCodeUnknownWebhookService                                            APIErrorCode = 10016
+	ErrCodeUnknownSession                                                   APIErrorCode = 10020
+	ErrCodeUnknownBan                                                       APIErrorCode = 10026
+	ErrCodeUnknownSKU                                                       APIErrorCode = 10027
+	ErrCodeUnknownStoreListing                                              APIErrorCode = 10028
+	ErrCodeUnknownEntitlement                                               APIErrorCode = 10029
+	ErrCodeUnknownBuild                                                     APIErrorCode = 10030
+	ErrCodeUnknownLobby                                                     APIErrorCode = 10031
+	ErrCodeUnknownBranch                                                    APIErrorCode = 10032
+	ErrCodeUnknownStoreDirectoryLayout                                      APIErrorCode = 10033
+	ErrCodeUnknownRedistributable                                           APIErrorCode = 10036
+	ErrCodeUnknownGiftCode                                                  APIErrorCode = 10038
+	ErrCodeUnknownStream                                                    APIErrorCode = 10049
+	ErrCodeUnknownPremiumServerSubscribeCooldown                            APIErrorCode = 10050
+	ErrCodeUnknownGuildTemplate                                             APIErrorCode = 10057
+	ErrCodeUnknownDiscoveryCategory                                         APIErrorCode = 10059
+	ErrCodeUnknownSticker                                                   APIErrorCode = 10060
+	ErrCodeUnknownInteraction                                               APIErrorCode = 10062
+	ErrCodeUnknownApplicationCommand                                        APIErrorCode = 10063
+	ErrCodeUnknownApplicationCommandPermissions                             APIErrorCode = 10066
+	ErrCodeUnknownStageInstance                                             APIErrorCode = 10067
+	ErrCodeUnknownGuildMemberVerificationForm                               APIErrorCode = 10068
+	ErrCodeUnknownGuildWelcomeScreen                                        APIErrorCode = 10069
+	ErrCodeUnknownGuildScheduledEvent                                       APIErrorCode = 10070
+	ErrCodeUnknownGuildScheduledEventUser                                   APIErrorCode = 10071
+	ErrUnknownTag                                                           APIErrorCode = 10087
+	ErrCodeBotsCannotUseEndpoint                                            APIErrorCode = 20001
+	ErrCodeOnlyBotsCanUseEndpoint                                           APIErrorCode = 20002
+	ErrCodeExplicitContentCannotBeSentToTheDesiredRecipients                APIErrorCode = 20009
+	ErrCodeYouAreNotAuthorizedToPerformThisActionOnThisApplication          APIErrorCode = 20012
+	ErrCodeThisActionCannotBePerformedDueToSlowmodeRateLimit                APIErrorCode = 20016
+	ErrCodeOnlyTheOwnerOfThisAccountCanPerformThisAction                    APIErrorCode = 20018
+	ErrCodeMessageCannotBeEditedDueToAnnouncementRateLimits                 APIErrorCode = 20022
+	ErrCodeChannelHasHitWriteRateLimit                                      APIErrorCode = 20028
+	ErrCodeTheWriteActionYouArePerformingOnTheServerHasHitTheWriteRateLimit APIErrorCode = 20029
+	ErrCodeStageTopicContainsNotAllowedWordsForPublicStages                 APIErrorCode = 20031
+	ErrCodeGuildPremiumSubscriptionLevelTooLow                              APIErrorCode = 20035
+	ErrCodeMaximumGuildsReached                                             APIErrorCode = 30001
+	ErrCodeMaximumPinsReached                                               APIErrorCode = 30003
+	ErrCodeMaximumNumberOfRecipientsReached                                 APIErrorCode = 30004
+	ErrCodeMaximumGuildRolesReached                                         APIErrorCode = 30005
+	ErrCodeMaximumNumberOfWebhooksReached                                   APIErrorCode = 30007
+	ErrCodeMaximumNumberOfEmojisReached                                     APIErrorCode = 30008
+	ErrCodeTooManyReactions                                                 APIErrorCode = 30010
+	ErrCodeMaximumNumberOfGuildChannelsReached                              APIErrorCode = 30013
+	ErrCodeMaximumNumberOfAttachmentsInAMessageReached                      APIErrorCode = 30015
+	ErrCodeMaximumNumberOfInvitesReached                                    APIErrorCode = 30016
+	ErrCodeMaximumNumberOfAnimatedEmojisReached                             APIErrorCode = 30018
+	ErrCodeMaximumNumberOfServerMembersReached                              APIErrorCode = 30019
+	ErrCodeMaximumNumberOfGuildDiscoverySubcategoriesReached                APIErrorCode = 30030
+	ErrCodeGuildAlreadyHasATemplate                                         APIErrorCode = 30031
+	ErrCodeMaximumNumberOfThreadParticipantsReached                         APIErrorCode = 30033
+	ErrCodeMaximumNumberOfBansForNonGuildMembersHaveBeenExceeded            APIErrorCode = 30035
+	ErrCodeMaximumNumberOfBansFetchesHasBeenReached                         APIErrorCode = 30037
+	ErrCodeMaximumNumberOfUncompletedGuildScheduledEventsReached            APIErrorCode = 30038
+	ErrCodeMaximumNumberOfStickersReached                                   APIErrorCode = 30039
+	ErrCodeMaximumNumberOfPruneRequestsHasBeenReached                       APIErrorCode = 30040
+	ErrCodeMaximumNumberOfGuildWidgetSettingsUpdatesHasBeenReached          APIErrorCode = 30042
+	ErrCodeMaximumNumberOfEditsToMessagesOlderThanOneHourReached            APIErrorCode = 30046
+	ErrCodeMaximumNumberOfPinnedThreadsInForumChannelHasBeenReached         APIErrorCode = 30047
+	ErrCodeMaximumNumberOfTagsInForumChannelHasBeenReached                  APIErrorCode = 30048
+	ErrCodeUnauthorized                                                     APIErrorCode = 40001
+	ErrCodeActionRequiredVerifiedAccount                                    APIErrorCode = 40002
+	ErrCodeOpeningDirectMessagesTooFast                                     APIErrorCode = 40003
+	ErrCodeSendMessagesHasBeenTemporarilyDisabled                           APIErrorCode = 40004
+	ErrCodeRequestEntityTooLarge                                            APIErrorCode = 40005
+	ErrCodeFeatureTemporarilyDisabledServerSide                             APIErrorCode = 40006
+	ErrCodeUserIsBannedFromThisGuild                                        APIErrorCode = 40007
+	ErrCodeTargetIsNotConnectedToVoice                                      APIErrorCode = 40032
+	ErrCodeMessageAlreadyCrossposted                                        APIErrorCode = 40033
+	ErrCodeAnApplicationWithThatNameAlreadyExists                           APIErrorCode = 40041
+	ErrCodeInteractionHasAlreadyBeenAcknowledged                            APIErrorCode = 40060
+	ErrCodeTagNamesMustBeUnique                                             APIErrorCode = 40061
+	ErrCodeMissingAccess                                                    APIErrorCode = 50001
+	ErrCodeInvalidAccountType                                               APIErrorCode = 50002
+	ErrCodeCannotExecuteActionOnDMChannel                                   APIErrorCode = 50003
+	ErrCodeEmbedDisabled                                                    APIErrorCode = 50004
+	ErrCodeGuildWidgetDisabled                                              APIErrorCode = 50004
+	ErrCodeCannotEditFromAnotherUser                                        APIErrorCode = 50005
+	ErrCodeCannotSendEmptyMessage                                           APIErrorCode = 50006
+	ErrCodeCannotSendMessagesToThisUser                                     APIErrorCode = 50007
+	ErrCodeCannotSendMessagesInVoiceChannel                                 APIErrorCode = 50008
+	ErrCodeChannelVerificationLevelTooHigh                                  APIErrorCode = 50009
+	ErrCodeOAuth2ApplicationDoesNotHaveBot                                  APIErrorCode = 50010
+	ErrCodeOAuth2ApplicationLimitReached                                    APIErrorCode = 50011
+	ErrCodeInvalidOAuthState                                                APIErrorCode = 50012
+	ErrCodeMissingPermissions                                               APIErrorCode = 50013
+	ErrCodeInvalidAuthenticationToken                                       APIErrorCode = 50014
+	ErrCodeTooFewOrTooManyMessagesToDelete                                  APIErrorCode = 50016
+	ErrCodeCanOnlyPinMessageToOriginatingChannel                            APIErrorCode = 50019
+	ErrCodeInviteCodeWasEitherInvalidOrTaken                                APIErrorCode = 50020
+	ErrCodeCannotExecuteActionOnSystemMessage                               APIErrorCode = 50021
+	ErrCodeCannotExecuteActionOnThisChannelType                             APIErrorCode = 50024
+	ErrCodeInvalidOAuth2AccessTokenProvided                                 APIErrorCode = 50025
+	ErrCodeMissingRequiredOAuth2Scope                                       APIErrorCode = 50026
+	ErrCodeInvalidWebhookTokenProvided                                      APIErrorCode = 50027
+	ErrCodeInvalidRole                                                      APIErrorCode = 50028
+	ErrCodeInvalidRecipients                                                APIErrorCode = 50033
+	ErrCodeMessageProvidedTooOldForBulkDelete                               APIErrorCode = 50034
+	ErrCodeInvalidFormBody                                                  APIErrorCode = 50035
+	ErrCodeInviteAcceptedToGuildApplicationsBotNotIn                        APIErrorCode = 50036
+	ErrCodeInvalidAPIVersionProvided                                        APIErrorCode = 50041
+	ErrCodeFileUploadedExceedsTheMaximumSize                                APIErrorCode = 50045
+	ErrCodeInvalidFileUploaded                                              APIErrorCode = 50046
+	ErrCodeInvalidGuild                                                     APIErrorCode = 50055
+	ErrCodeInvalidMessageType                                               APIErrorCode = 50068
+	ErrCodeCannotDeleteAChannelRequiredForCommunityGuilds                   APIErrorCode = 50074
+	ErrCodeInvalidStickerSent                                               APIErrorCode = 50081
+	ErrCodePerformedOperationOnArchivedThread                               APIErrorCode = 50083
+	ErrCodeBeforeValueIsEarlierThanThreadCreationDate                       APIErrorCode = 50085
+	ErrCodeCommunityServerChannelsMustBeTextChannels                        APIErrorCode = 50086
+	ErrCodeThisServerIsNotAvailableInYourLocation                           APIErrorCode = 50095
+	ErrCodeThisServerNeedsMonetizationEnabledInOrderToPerformThisAction     APIErrorCode = 50097
+	ErrCodeThisServerNeedsMoreBoostsToPerformThisAction                     APIErrorCode = 50101
+	ErrCodeTheRequestBodyContainsInvalidJSON                                APIErrorCode = 50109
+	ErrCodeNoUsersWithDiscordTagExist                                       APIErrorCode = 80004
+	ErrCodeReactionBlocked                                                  APIErrorCode = 90001
+	ErrCodeAPIResourceIsCurrentlyOverloaded                                 APIErrorCode = 130000
+	ErrCodeTheStageIsAlreadyOpen                                            APIErrorCode = 150006
+	ErrCodeCannotReplyWithoutPermissionToReadMessageHistory                 APIErrorCode = 160002
+	ErrCodeThreadAlreadyCreatedForThisMessage                               APIErrorCode = 160004
+	ErrCodeThreadIsLocked                                                   APIErrorCode = 160005
+	ErrCodeMaximumNumberOfActiveThreadsReached                              APIErrorCode = 160006
+	ErrCodeMaximumNumberOfActiveAnnouncementThreadsReached                  APIErrorCode = 160007
+	ErrCodeInvalidJSONForUploadedLottieFile                                 APIErrorCode = 170001
+	ErrCodeUploadedLottiesCannotContainRasterizedImages                     APIErrorCode = 170002
+	ErrCodeStickerMaximumFramerateExceeded                                  APIErrorCode = 170003
+	ErrCodeStickerFrameCountExceedsMaximumOfOneThousandFrames               APIErrorCode = 170004
+	ErrCodeLottieAnimationMaximumDimensionsExceeded                         APIErrorCode = 170005
+	ErrCodeStickerFrameRateOutOfRange                                       APIErrorCode = 170006
+	ErrCodeStickerAnimationDurationExceedsMaximumOfFiveSeconds              APIErrorCode = 170007
+	ErrCodeCannotUpdateAFinishedEvent                                       APIErrorCode = 180000
+	ErrCodeFailedToCreateStageNeededForStageEvent                           APIErrorCode = 180002
+	ErrCodeCannotEnableOnboardingRequirementsAreNotMet                      APIErrorCode = 350000
+	ErrCodeCannotUpdateOnboardingWhileBelowRequirements                     APIErrorCode = 350001
 )
 
 type Intent int
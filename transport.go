@@ -0,0 +1,48 @@
+package discordgo
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+type GatewayConn interface {
+	ReadMessage() (messageType int, p []byte, err error)
+	WriteMessage(messageType int, data []byte) error
+	Close() error
+}
+
+type GatewayDialer interface {
+	DialGateway(ctx context.Context, url string, header http.Header) (GatewayConn, error)
+}
+
+type WebsocketDialer struct {
+	Dialer *websocket.Dialer
+}
+
+func (d WebsocketDialer) DialGateway(ctx context.Context, url string, header http.Header) (GatewayConn, error) {
+	dialer := d.Dialer
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+
+	conn, _, err := dialer.DialContext(ctx, url, header)
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetCloseHandler(func(code int, text string) error {
+		return nil
+	})
+
+	return conn, nil
+}
+
+func (s *Session) gatewayDialer() GatewayDialer {
+	if s.GatewayDialer != nil {
+		return s.GatewayDialer
+	}
+
+	return WebsocketDialer{s.Dialer}
+}
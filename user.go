@@ -201,6 +201,17 @@ func SnowflakeTimestamp(ID string) (time.Time, error) {
 	return time.UnixMilli(ms), nil
 }
 
+func SnowflakeFromTime(t time.Time) string {
+	const epoch = 1420070400000
+
+	ms := t.UnixNano()/int64(time.Millisecond) - epoch
+	if ms < 0 {
+		ms = 0
+	}
+
+	return strconv.FormatInt(ms<<22, 10)
+}
+
 func MultipartBodyWithJSON(data interface{}, files []*File) (string, []byte, error) {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
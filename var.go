@@ -1,8 +1,13 @@
 package discordgo
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"io"
+)
 
 var (
 	Marshal   func(v interface{}) ([]byte, error)   = json.Marshal
 	Unmarshal func(src []byte, v interface{}) error = json.Unmarshal
 )
+
+var NewZstdReader func(r io.Reader) (io.ReadCloser, error)
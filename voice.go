@@ -83,9 +83,7 @@ func (v *VoiceConnection) ChangeChannel(channelID string, mute, deaf bool) (err
 	v.log(LogInformational, "called")
 
 	data := voiceChannelJoinOp{4, voiceChannelJoinData{&v.GuildID, &channelID, mute, deaf}}
-	v.session.wsMutex.Lock()
-	err = v.session.wsConn.WriteJSON(data)
-	v.session.wsMutex.Unlock()
+	err = v.session.writeGateway(v.session.wsConn, data)
 	if err != nil {
 		return
 	}
@@ -101,9 +99,7 @@ func (v *VoiceConnection) Disconnect() (err error) {
 	v.Lock()
 	if v.sessionID != "" {
 		data := voiceChannelJoinOp{4, voiceChannelJoinData{&v.GuildID, nil, true, true}}
-		v.session.wsMutex.Lock()
-		err = v.session.wsConn.WriteJSON(data)
-		v.session.wsMutex.Unlock()
+		err = v.session.writeGateway(v.session.wsConn, data)
 		v.sessionID = ""
 	}
 
@@ -735,14 +731,11 @@ func (v *VoiceConnection) reconnect() {
 
 	v.Close()
 
-	wait := time.Duration(1)
-	for {
+	for attempt := 1; ; attempt++ {
+		wait := v.session.backoff(attempt)
+		v.session.handleEvent(reconnectAttemptEventType, &ReconnectAttempt{Attempt: attempt, Delay: wait, GuildID: v.GuildID})
 
-		<-time.After(wait * time.Second)
-		wait *= 2
-		if wait > 600 {
-			wait = 600
-		}
+		<-time.After(wait)
 
 		if !v.session.DataReady || v.session.wsConn == nil {
 			v.log(LogInformational, "cannot reconnect to channel %s with unready session", v.ChannelID)
@@ -760,9 +753,7 @@ func (v *VoiceConnection) reconnect() {
 		v.log(LogInformational, "error reconnecting to channel %s, %s", v.ChannelID, err)
 
 		data := voiceChannelJoinOp{4, voiceChannelJoinData{&v.GuildID, nil, true, true}}
-		v.session.wsMutex.Lock()
-		err = v.session.wsConn.WriteJSON(data)
-		v.session.wsMutex.Unlock()
+		err = v.session.writeGateway(v.session.wsConn, data)
 		if err != nil {
 			v.log(LogError, "error sending disconnect packet, %s", err)
 		}
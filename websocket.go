@@ -3,16 +3,88 @@ package discordgo
 import (
 	"bytes"
 	"compress/zlib"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
 )
 
+type GatewayCloseCode int
+
+const (
+	GatewayCloseUnknownError         GatewayCloseCode = 4000
+	GatewayCloseUnknownOpcode        GatewayCloseCode = 4001
+	GatewayCloseDecodeError          GatewayCloseCode = 4002
+	GatewayCloseNotAuthenticated     GatewayCloseCode = 4003
+	GatewayCloseAuthenticationFailed GatewayCloseCode = 4004
+	GatewayCloseAlreadyAuthenticated GatewayCloseCode = 4005
+	GatewayCloseInvalidSeq           GatewayCloseCode = 4007
+	GatewayCloseRateLimited          GatewayCloseCode = 4008
+	GatewayCloseSessionTimedOut      GatewayCloseCode = 4009
+	GatewayCloseInvalidShard         GatewayCloseCode = 4010
+	GatewayCloseShardingRequired     GatewayCloseCode = 4011
+	GatewayCloseInvalidAPIVersion    GatewayCloseCode = 4012
+	GatewayCloseInvalidIntents       GatewayCloseCode = 4013
+	GatewayCloseDisallowedIntents    GatewayCloseCode = 4014
+)
+
+func (c GatewayCloseCode) Fatal() bool {
+	switch c {
+	case GatewayCloseAuthenticationFailed,
+		GatewayCloseInvalidShard,
+		GatewayCloseShardingRequired,
+		GatewayCloseInvalidAPIVersion,
+		GatewayCloseInvalidIntents,
+		GatewayCloseDisallowedIntents:
+		return true
+	}
+
+	return false
+}
+
+func (c GatewayCloseCode) Resumable() bool {
+	switch c {
+	case GatewayCloseInvalidSeq, GatewayCloseSessionTimedOut:
+		return false
+	}
+
+	return !c.Fatal()
+}
+
+type GatewayCloseError struct {
+	Code      GatewayCloseCode
+	Text      string
+	Resumable bool
+	Fatal     bool
+}
+
+func newGatewayCloseError(err error) error {
+	ce, ok := err.(*websocket.CloseError)
+	if !ok {
+		return err
+	}
+
+	code := GatewayCloseCode(ce.Code)
+	return &GatewayCloseError{
+		Code:      code,
+		Text:      ce.Text,
+		Resumable: code.Resumable(),
+		Fatal:     code.Fatal(),
+	}
+}
+
+func (e GatewayCloseError) Error() string {
+	return fmt.Sprintf("gateway closed with code %d, %s", e.Code, e.Text)
+}
+
 type resumePacket struct {
 	Op   int `json:"op"`
 	Data struct {
@@ -23,6 +95,10 @@ type resumePacket struct {
 }
 
 func (s *Session) Open() error {
+	return s.OpenContext(context.Background())
+}
+
+func (s *Session) OpenContext(ctx context.Context) (err error) {
 	s.log(LogInformational, "called")
 
 	s.Lock()
@@ -32,44 +108,76 @@ func (s *Session) Open() error {
 		return ErrWSAlreadyOpen
 	}
 
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
 	if s.gateway == "" {
-		var err error
-		s.gateway, err = s.Gateway()
+		s.gateway, err = s.Gateway(WithContext(ctx))
 		if err != nil {
 			return err
 		}
-		s.gateway = s.gateway + "?v=" + APIVersion + "&encoding=json"
 	}
 
-	s.log(LogInformational, "connecting to gateway %s", s.gateway)
+	if s.Compression != GatewayCompressionNone {
+		inflater, err := newStreamInflater(s.Compression, s.Encoding)
+		if err != nil {
+			return err
+		}
+
+		s.inflaterMu.Lock()
+		s.inflater = inflater
+		s.inflaterMu.Unlock()
+	}
+
+	sequence := atomic.LoadInt64(s.sequence)
+	resuming := s.sessionID != "" && sequence != 0
+
+	base := s.gateway
+	if resuming && s.resumeGatewayURL != "" {
+		base = s.resumeGatewayURL
+	}
+
+	gateway := s.gatewayURL(base)
+	s.log(LogInformational, "connecting to gateway %s", gateway)
 	header := http.Header{}
 	header.Add("accept-encoding", "zlib")
-	var err error
-	s.wsConn, _, err = s.Dialer.Dial(s.gateway, header)
+	s.wsConn, err = s.gatewayDialer().DialGateway(ctx, gateway, header)
 	if err != nil {
-		s.log(LogError, "error connecting to gateway %s, %s", s.gateway, err)
-		s.gateway = ""
+		s.log(LogError, "error connecting to gateway %s, %s", gateway, err)
+		if base == s.resumeGatewayURL {
+			s.resumeGatewayURL = ""
+		} else {
+			s.gateway = ""
+		}
 		s.wsConn = nil
+		s.closeInflater()
 		return err
 	}
 
-	s.wsConn.SetCloseHandler(func(code int, text string) error {
-		return nil
-	})
+	if s.GatewayRateLimiter != nil {
+		if err = s.GatewayRateLimiter.Reset(); err != nil {
+			s.wsConn.Close()
+			s.wsConn = nil
+			s.closeInflater()
+			return err
+		}
+	}
 
+	stopWatch := watchHandshake(ctx, s.wsConn)
 	defer func() {
+		stopWatch()
 		if err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
 			s.wsConn.Close()
 			s.wsConn = nil
+			s.closeInflater()
 		}
 	}()
 
-	mt, m, err := s.wsConn.ReadMessage()
-	if err != nil {
-		return err
-	}
-
-	e, err := s.onEvent(mt, m)
+	e, err := s.readEvent(s.wsConn)
 	if err != nil {
 		return err
 	}
@@ -81,13 +189,13 @@ func (s *Session) Open() error {
 	s.LastHeartbeatAck = time.Now().UTC()
 
 	var h helloOp
-	if err := Unmarshal(e.RawData, &h); err != nil {
+	if err := e.unmarshal(&h); err != nil {
 		return fmt.Errorf("error unmarshalling helloOp: %s", err)
 	}
 
-	sequence := atomic.LoadInt64(s.sequence)
-	if s.sessionID == "" && sequence == 0 {
-		if err := s.identify(); err != nil {
+	if !resuming {
+		s.resetSession()
+		if err := s.identifyContext(ctx); err != nil {
 			return fmt.Errorf("error sending identify packet to gateway: %s - %s", s.gateway, err)
 		}
 	} else {
@@ -105,9 +213,8 @@ func (s *Session) Open() error {
 		}
 
 		s.log(LogInformational, "Sending resume packet to gateway...")
-		s.wsMutex.Lock()
-		err := s.wsConn.WriteJSON(p)
-		s.wsMutex.Unlock()
+		s.handleEvent(resumingEventType, &Resuming{SessionID: s.sessionID, Sequence: sequence})
+		err := s.writeGatewayContext(ctx, s.wsConn, p)
 		if err != nil {
 			return fmt.Errorf("error sending gateway resume packet: %s - %s", s.gateway, err)
 		}
@@ -120,14 +227,10 @@ func (s *Session) Open() error {
 		s.State.TrackMembers = false
 		s.State.TrackRoles = false
 		s.State.TrackVoice = false
+		s.State.TrackSoundboard = false
 	}
 
-	mt, m, err = s.wsConn.ReadMessage()
-	if err != nil {
-		return err
-	}
-
-	e, err = s.onEvent(mt, m)
+	e, err = s.readEvent(s.wsConn)
 	if err != nil {
 		return err
 	}
@@ -137,6 +240,11 @@ func (s *Session) Open() error {
 	}
 	s.log(LogInformational, "First Packet:\n%#v\n", e)
 
+	stopWatch()
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
 	s.log(LogInformational, "Connected to Discord, emitting connect event...")
 	s.handleEvent(connectEventType, &Connect{})
 
@@ -153,7 +261,121 @@ func (s *Session) Open() error {
 	return nil
 }
 
-func (s *Session) listen(wsConn *websocket.Conn, listening <-chan interface{}) {
+func watchHandshake(ctx context.Context, wsConn GatewayConn) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			wsConn.Close()
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+		})
+	}
+}
+
+func (s *Session) gatewayURL(base string) string {
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+
+	encoding := s.Encoding
+	if encoding == "" {
+		encoding = GatewayEncodingJSON
+	}
+
+	gateway := base + "?v=" + APIVersion + "&encoding=" + string(encoding)
+	if s.Compression != GatewayCompressionNone {
+		gateway += "&compress=" + string(s.Compression)
+	}
+
+	return gateway
+}
+
+func (s *Session) readEvent(wsConn GatewayConn) (*Event, error) {
+	for {
+		mt, m, err := wsConn.ReadMessage()
+		if err != nil {
+			return nil, newGatewayCloseError(err)
+		}
+
+		e, err := s.onEvent(mt, m)
+		if err != nil || e != nil {
+			return e, err
+		}
+	}
+}
+
+func (s *Session) writeGateway(wsConn GatewayConn, data interface{}) error {
+	return s.writeGatewayContext(context.Background(), wsConn, data)
+}
+
+func (s *Session) writeGatewayContext(ctx context.Context, wsConn GatewayConn, data interface{}) error {
+	if wsConn == nil {
+		return ErrWSNotFound
+	}
+
+	if s.GatewayRateLimiter != nil {
+		if err := s.GatewayRateLimiter.WaitContext(ctx, gatewayPriority(data)); err != nil {
+			return err
+		}
+	}
+
+	s.wsMutex.Lock()
+	defer s.wsMutex.Unlock()
+
+	if s.Encoding == GatewayEncodingETF {
+		b, err := ETFMarshal(data)
+		if err != nil {
+			return err
+		}
+
+		return wsConn.WriteMessage(websocket.BinaryMessage, b)
+	}
+
+	b, err := Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return wsConn.WriteMessage(websocket.TextMessage, b)
+}
+
+func gatewayPriority(data interface{}) bool {
+	switch data.(type) {
+	case heartbeatOp, identifyOp, resumePacket:
+		return true
+	}
+
+	return false
+}
+
+func (s *Session) resetSession() {
+	s.sessionID = ""
+	s.resumeGatewayURL = ""
+	atomic.StoreInt64(s.sequence, 0)
+}
+
+func (s *Session) closeInflater() {
+	s.inflaterMu.Lock()
+	defer s.inflaterMu.Unlock()
+
+	if s.inflater != nil {
+		s.inflater.Close()
+		s.inflater = nil
+	}
+}
+
+func (s *Session) listen(wsConn GatewayConn, listening <-chan interface{}) {
 	s.log(LogInformational, "called")
 
 	for {
@@ -164,8 +386,18 @@ func (s *Session) listen(wsConn *websocket.Conn, listening <-chan interface{}) {
 			s.RUnlock()
 
 			if sameConnection {
+				err = newGatewayCloseError(err)
 				s.log(LogWarning, "error reading from gateway %s websocket, %s", s.gateway, err)
-				err := s.Close()
+
+				if ce, ok := err.(*GatewayCloseError); ok && ce.Fatal {
+					s.closeWithCode(websocket.CloseNormalClosure)
+					s.fatalClose(ce)
+					return
+				} else if ok && !ce.Resumable {
+					s.resetSession()
+				}
+
+				err := s.closeWithCode(websocket.CloseServiceRestart)
 				if err != nil {
 					s.log(LogWarning, "error closing session connection, %s", err)
 				}
@@ -204,7 +436,7 @@ func (s *Session) HeartbeatLatency() time.Duration {
 
 }
 
-func (s *Session) heartbeat(wsConn *websocket.Conn, listening <-chan interface{}, heartbeatInterval time.Duration) {
+func (s *Session) heartbeat(wsConn GatewayConn, listening <-chan interface{}, heartbeatInterval time.Duration) {
 	s.log(LogInformational, "called")
 
 	if listening == nil || wsConn == nil {
@@ -221,17 +453,25 @@ func (s *Session) heartbeat(wsConn *websocket.Conn, listening <-chan interface{}
 		s.RUnlock()
 		sequence := atomic.LoadInt64(s.sequence)
 		s.log(LogDebug, "sending gateway websocket heartbeat seq %d", sequence)
-		s.wsMutex.Lock()
 		s.LastHeartbeatSent = time.Now().UTC()
-		err = wsConn.WriteJSON(heartbeatOp{1, sequence})
-		s.wsMutex.Unlock()
+		err = s.writeGateway(wsConn, heartbeatOp{1, sequence})
 		if err != nil || time.Now().UTC().Sub(last) > (heartbeatInterval*FailedHeartbeatAcks) {
+			s.RLock()
+			sameConnection := s.wsConn == wsConn
+			s.RUnlock()
+
+			if !sameConnection {
+				return
+			}
+
 			if err != nil {
 				s.log(LogError, "error sending heartbeat to gateway %s, %s", s.gateway, err)
 			} else {
-				s.log(LogError, "haven't gotten a heartbeat ACK in %v, triggering a reconnection", time.Now().UTC().Sub(last))
+				elapsed := time.Now().UTC().Sub(last)
+				s.log(LogError, "haven't gotten a heartbeat ACK in %v, triggering a reconnection", elapsed)
+				s.handleEvent(heartbeatAckMissedEventType, &HeartbeatAckMissed{LastHeartbeatAck: last, Elapsed: elapsed})
 			}
-			s.Close()
+			s.closeWithCode(websocket.CloseServiceRestart)
 			s.reconnect()
 			return
 		}
@@ -321,14 +561,10 @@ func (s *Session) UpdateStatusComplex(usd UpdateStatusData) (err error) {
 	}
 
 	s.RLock()
-	defer s.RUnlock()
-	if s.wsConn == nil {
-		return ErrWSNotFound
-	}
+	wsConn := s.wsConn
+	s.RUnlock()
 
-	s.wsMutex.Lock()
-	err = s.wsConn.WriteJSON(updateStatusOp{3, usd})
-	s.wsMutex.Unlock()
+	err = s.writeGateway(wsConn, updateStatusOp{3, usd})
 
 	return
 }
@@ -379,16 +615,30 @@ func (s *Session) RequestGuildMembersBatchList(guildIDs []string, userIDs []stri
 	return
 }
 
+type requestSoundboardSoundsData struct {
+	GuildIDs []string `json:"guild_ids"`
+}
+
+type requestSoundboardSoundsOp struct {
+	Op   int                         `json:"op"`
+	Data requestSoundboardSoundsData `json:"d"`
+}
+
+func (s *Session) RequestSoundboardSounds(guildIDs []string) (err error) {
+	s.RLock()
+	wsConn := s.wsConn
+	s.RUnlock()
+
+	err = s.writeGateway(wsConn, requestSoundboardSoundsOp{31, requestSoundboardSoundsData{guildIDs}})
+	return
+}
+
 func (s *Session) GatewayWriteStruct(data interface{}) (err error) {
 	s.RLock()
-	defer s.RUnlock()
-	if s.wsConn == nil {
-		return ErrWSNotFound
-	}
+	wsConn := s.wsConn
+	s.RUnlock()
 
-	s.wsMutex.Lock()
-	err = s.wsConn.WriteJSON(data)
-	s.wsMutex.Unlock()
+	err = s.writeGateway(wsConn, data)
 
 	return err
 }
@@ -397,24 +647,112 @@ func (s *Session) requestGuildMembers(data requestGuildMembersData) (err error)
 	s.log(LogInformational, "called")
 
 	s.RLock()
-	defer s.RUnlock()
-	if s.wsConn == nil {
-		return ErrWSNotFound
-	}
+	wsConn := s.wsConn
+	s.RUnlock()
 
-	s.wsMutex.Lock()
-	err = s.wsConn.WriteJSON(requestGuildMembersOp{8, data})
-	s.wsMutex.Unlock()
+	err = s.writeGateway(wsConn, requestGuildMembersOp{8, data})
 
 	return
 }
 
+type GuildMembersResult struct {
+	GuildID   string
+	Members   []*Member
+	NotFound  []string
+	Presences []*Presence
+}
+
+type memberChunkWaiter struct {
+	result   *GuildMembersResult
+	received map[int]bool
+	done     chan struct{}
+}
+
+func (s *Session) FetchGuildMembers(ctx context.Context, guildID, query string, userIDs []string, limit int, presences bool) (*GuildMembersResult, error) {
+	nonce := fmt.Sprintf("%016x", rand.Uint64())
+	w := &memberChunkWaiter{
+		result:   &GuildMembersResult{GuildID: guildID},
+		received: make(map[int]bool),
+		done:     make(chan struct{}),
+	}
+
+	s.chunkMu.Lock()
+	if s.chunkWaiters == nil {
+		s.chunkWaiters = make(map[string]*memberChunkWaiter)
+	}
+	s.chunkWaiters[nonce] = w
+	s.chunkMu.Unlock()
+
+	defer func() {
+		s.chunkMu.Lock()
+		delete(s.chunkWaiters, nonce)
+		s.chunkMu.Unlock()
+	}()
+
+	var err error
+	if userIDs != nil {
+		err = s.RequestGuildMembersList(guildID, userIDs, limit, nonce, presences)
+	} else {
+		err = s.RequestGuildMembers(guildID, query, limit, nonce, presences)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-w.done:
+		return w.result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *Session) onGuildMembersChunk(c *GuildMembersChunk) {
+	if c.Nonce == "" {
+		return
+	}
+
+	s.chunkMu.Lock()
+	defer s.chunkMu.Unlock()
+
+	w, ok := s.chunkWaiters[c.Nonce]
+	if !ok || w.received[c.ChunkIndex] {
+		return
+	}
+	w.received[c.ChunkIndex] = true
+
+	w.result.Members = append(w.result.Members, c.Members...)
+	w.result.NotFound = append(w.result.NotFound, c.NotFound...)
+	w.result.Presences = append(w.result.Presences, c.Presences...)
+
+	if len(w.received) >= c.ChunkCount {
+		delete(s.chunkWaiters, c.Nonce)
+		close(w.done)
+	}
+}
+
 func (s *Session) onEvent(messageType int, message []byte) (*Event, error) {
 	var err error
 	var reader io.Reader
 	reader = bytes.NewBuffer(message)
 
-	if messageType == websocket.BinaryMessage {
+	s.inflaterMu.Lock()
+	inflater := s.inflater
+	s.inflaterMu.Unlock()
+
+	if inflater != nil && messageType == websocket.BinaryMessage {
+		payload, err := inflater.inflate(message)
+		if err != nil {
+			s.log(LogError, "error inflating websocket message, %s", err)
+			return nil, err
+		}
+
+		if payload == nil {
+			return nil, nil
+		}
+
+		reader = bytes.NewReader(payload)
+	} else if messageType == websocket.BinaryMessage && (s.Encoding != GatewayEncodingETF || len(message) == 0 || message[0] != etfVersion) {
 
 		z, err2 := zlib.NewReader(reader)
 		if err2 != nil {
@@ -433,19 +771,42 @@ func (s *Session) onEvent(messageType int, message []byte) (*Event, error) {
 	}
 
 	var e *Event
-	decoder := json.NewDecoder(reader)
-	if err = decoder.Decode(&e); err != nil {
-		s.log(LogError, "error decoding websocket message, %s", err)
-		return e, err
+	if s.Encoding == GatewayEncodingETF {
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			s.log(LogError, "error reading websocket message, %s", err)
+			return nil, err
+		}
+
+		e, err = etfEvent(data)
+		if err != nil {
+			s.log(LogError, "error decoding etf websocket message, %s", err)
+			return nil, err
+		}
+
+		if s.Recorder != nil || s.LogLevel >= LogDebug || s.hasEventHandlers() {
+			e.RawData, err = etfJSON(e.term)
+			if err != nil {
+				s.log(LogWarning, "error encoding etf payload as json, %s", err)
+			}
+		}
+	} else {
+		decoder := json.NewDecoder(reader)
+		if err = decoder.Decode(&e); err != nil {
+			s.log(LogError, "error decoding websocket message, %s", err)
+			return e, err
+		}
 	}
 
 	s.log(LogDebug, "Op: %d, Seq: %d, Type: %s, Data: %s\n\n", e.Operation, e.Sequence, e.Type, string(e.RawData))
 
+	if s.Recorder != nil {
+		s.record(e)
+	}
+
 	if e.Operation == 1 {
 		s.log(LogInformational, "sending heartbeat in response to Op1")
-		s.wsMutex.Lock()
-		err = s.wsConn.WriteJSON(heartbeatOp{1, atomic.LoadInt64(s.sequence)})
-		s.wsMutex.Unlock()
+		err = s.writeGateway(s.wsConn, heartbeatOp{1, atomic.LoadInt64(s.sequence)})
 		if err != nil {
 			s.log(LogError, "error sending heartbeat in response to Op1")
 			return e, err
@@ -456,21 +817,31 @@ func (s *Session) onEvent(messageType int, message []byte) (*Event, error) {
 
 	if e.Operation == 7 {
 		s.log(LogInformational, "Closing and reconnecting in response to Op7")
-		s.CloseWithCode(websocket.CloseServiceRestart)
+		s.handleEvent(reconnectRequestedEventType, &ReconnectRequested{})
+		s.closeWithCode(websocket.CloseServiceRestart)
 		s.reconnect()
 		return e, nil
 	}
 
 	if e.Operation == 9 {
+		var resumable bool
+		if err := e.unmarshal(&resumable); err != nil {
+			s.log(LogWarning, "error unmarshalling Op9 payload, %s", err)
+		}
+		s.handleEvent(invalidSessionEventType, &InvalidSession{Resumable: resumable})
 
-		s.log(LogInformational, "sending identify packet to gateway in response to Op9")
-
-		err = s.identify()
-		if err != nil {
-			s.log(LogWarning, "error sending gateway identify packet, %s, %s", s.gateway, err)
-			return e, err
+		if resumable {
+			s.log(LogInformational, "Closing and resuming in response to resumable Op9")
+			go func() {
+				s.closeWithCode(websocket.CloseServiceRestart)
+				s.reconnect()
+			}()
+			return e, nil
 		}
 
+		s.resetSession()
+		s.scheduleReidentify(time.Duration(1000+rand.Intn(4000)) * time.Millisecond)
+
 		return e, nil
 	}
 
@@ -491,22 +862,45 @@ func (s *Session) onEvent(messageType int, message []byte) (*Event, error) {
 		return e, nil
 	}
 
+	s.dispatch(e)
+
+	return e, nil
+}
+
+func (s *Session) dispatch(e *Event) {
 	atomic.StoreInt64(s.sequence, e.Sequence)
+	s.dispatchEvent(e, true, s.SyncEvents)
+}
 
+func (s *Session) dispatchEvent(e *Event, live, synchronous bool) {
+	var ready *GuildsReady
 	if eh, ok := registeredInterfaceProviders[e.Type]; ok {
 		e.Struct = eh.New()
 
-		if err = Unmarshal(e.RawData, e.Struct); err != nil {
+		if err := e.unmarshal(e.Struct); err != nil {
 			s.log(LogError, "error unmarshalling %s event, %s", e.Type, err)
 		}
-		s.handleEvent(e.Type, e.Struct)
+
+		if live {
+			if r, ok := e.Struct.(*Ready); ok {
+				s.onReady(r)
+			}
+			ready = s.trackGuilds(e.Struct)
+		}
+		s.handleEventSync(e.Type, e.Struct, synchronous)
 	} else {
 		s.log(LogWarning, "unknown event: Op: %d, Seq: %d, Type: %s, Data: %s", e.Operation, e.Sequence, e.Type, string(e.RawData))
 	}
 
-	s.handleEvent(eventEventType, e)
+	s.handleEventSync(eventEventType, e, synchronous)
 
-	return e, nil
+	if e.Type == readyEventType || e.Type == resumedEventType {
+		s.handleEventSync(shardReadyEventType, &ShardReady{ShardID: s.ShardID, ShardCount: s.ShardCount, Resumed: e.Type == resumedEventType}, synchronous)
+	}
+
+	if ready != nil {
+		s.handleEventSync(guildsReadyEventType, ready, synchronous)
+	}
 }
 
 type voiceChannelJoinData struct {
@@ -569,9 +963,7 @@ func (s *Session) ChannelVoiceJoinManual(gID, cID string, mute, deaf bool) (err
 	}
 
 	data := voiceChannelJoinOp{4, voiceChannelJoinData{&gID, channelID, mute, deaf}}
-	s.wsMutex.Lock()
-	err = s.wsConn.WriteJSON(data)
-	s.wsMutex.Unlock()
+	err = s.writeGateway(s.wsConn, data)
 	return
 }
 
@@ -628,10 +1020,54 @@ type identifyOp struct {
 	Data Identify `json:"d"`
 }
 
+func (s *Session) scheduleReidentify(delay time.Duration) {
+	s.log(LogInformational, "sending identify packet to gateway in %v in response to Op9", delay)
+
+	s.wsMutex.Lock()
+	defer s.wsMutex.Unlock()
+
+	if s.reidentify != nil {
+		s.reidentify.Stop()
+	}
+
+	var t *time.Timer
+	t = time.AfterFunc(delay, func() {
+		s.wsMutex.Lock()
+		current := s.reidentify == t
+		if current {
+			s.reidentify = nil
+		}
+		s.wsMutex.Unlock()
+
+		if !current {
+			return
+		}
+
+		if err := s.identify(); err != nil {
+			s.log(LogWarning, "error sending gateway identify packet, %s, %s", s.gateway, err)
+		}
+	})
+	s.reidentify = t
+}
+
+func (s *Session) stopReidentify() {
+	s.wsMutex.Lock()
+	defer s.wsMutex.Unlock()
+
+	if s.reidentify != nil {
+		s.reidentify.Stop()
+		s.reidentify = nil
+	}
+}
+
 func (s *Session) identify() error {
+	return s.identifyContext(context.Background())
+}
+
+func (s *Session) identifyContext(ctx context.Context) error {
 	s.log(LogDebug, "called")
 
-	if !s.Compress {
+	if !s.Compress || s.Compression != GatewayCompressionNone {
 		s.Identify.Compress = false
 	}
 
@@ -648,11 +1084,17 @@ func (s *Session) identify() error {
 		s.Identify.Shard = &[2]int{s.ShardID, s.ShardCount}
 	}
 
+	if s.manager != nil {
+		if err := s.manager.identifyWait(ctx, s.ShardID); err != nil {
+			return err
+		}
+	}
+
+	s.handleEvent(identifyingEventType, &Identifying{ShardID: s.ShardID, ShardCount: s.ShardCount})
+
 	op := identifyOp{2, s.Identify}
 	s.log(LogDebug, "Identify Packet: \n%#v", op)
-	s.wsMutex.Lock()
-	err := s.wsConn.WriteJSON(op)
-	s.wsMutex.Unlock()
+	err := s.writeGatewayContext(ctx, s.wsConn, op)
 
 	return err
 }
@@ -663,13 +1105,20 @@ func (s *Session) reconnect() {
 	var err error
 
 	if s.ShouldReconnectOnError {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
 
-		wait := time.Duration(1)
+		s.reconnectMu.Lock()
+		if s.cancelReconnect != nil {
+			s.cancelReconnect()
+		}
+		s.cancelReconnect = cancel
+		s.reconnectMu.Unlock()
 
-		for {
+		for attempt := 1; ; attempt++ {
 			s.log(LogInformational, "trying to reconnect to gateway")
 
-			err = s.Open()
+			err = s.OpenContext(ctx)
 			if err == nil {
 				s.log(LogInformational, "successfully reconnected to gateway")
 				if s.ShouldReconnectVoiceOnSessionError {
@@ -689,26 +1138,68 @@ func (s *Session) reconnect() {
 				return
 			}
 
+			if ctx.Err() != nil {
+				s.log(LogInformational, "reconnect cancelled")
+				return
+			}
+
+			if ce, ok := err.(*GatewayCloseError); ok && ce.Fatal {
+				s.fatalClose(ce)
+				return
+			} else if ok && !ce.Resumable {
+				s.resetSession()
+			}
+
 			s.log(LogError, "error reconnecting to gateway, %s", err)
 
-			<-time.After(wait * time.Second)
-			wait *= 2
-			if wait > 600 {
-				wait = 600
+			wait := s.backoff(attempt)
+			s.handleEvent(reconnectAttemptEventType, &ReconnectAttempt{Attempt: attempt, Delay: wait, Err: err})
+
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				s.log(LogInformational, "reconnect cancelled")
+				return
 			}
 		}
 	}
 }
 
+func (s *Session) fatalClose(ce *GatewayCloseError) {
+	s.log(LogError, "gateway closed with fatal code %d, not reconnecting, %s", ce.Code, ce.Text)
+	s.resetSession()
+	s.handleEvent(gatewayFatalCloseEventType, &GatewayFatalClose{Code: ce.Code, Text: ce.Text})
+}
+
 func (s *Session) Close() error {
 	return s.CloseWithCode(websocket.CloseNormalClosure)
 }
 
-func (s *Session) CloseWithCode(closeCode int) (err error) {
+func (s *Session) CloseWithCode(closeCode int) error {
+	s.stopReconnect()
+	return s.closeWithCode(closeCode)
+}
+
+func (s *Session) stopReconnect() {
+	s.reconnectMu.Lock()
+	defer s.reconnectMu.Unlock()
+
+	if s.cancelReconnect != nil {
+		s.cancelReconnect()
+		s.cancelReconnect = nil
+	}
+}
+
+func (s *Session) closeWithCode(closeCode int) (err error) {
 	s.log(LogInformational, "called")
 	s.Lock()
 
 	s.DataReady = false
+	s.stopReidentify()
+
+	if closeCode == websocket.CloseNormalClosure || closeCode == websocket.CloseGoingAway {
+		s.resetSession()
+	}
 
 	if s.listening != nil {
 		s.log(LogInformational, "closing listening channel")
@@ -735,6 +1226,7 @@ func (s *Session) CloseWithCode(closeCode int) (err error) {
 		}
 
 		s.wsConn = nil
+		s.closeInflater()
 	}
 
 	s.Unlock()
@@ -0,0 +1,363 @@
+package discordgo
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testResumeGatewayURL = "wss://resume.gateway.test"
+
+func newGatewayTestSession(t *testing.T) (*Session, *fakeGateway) {
+	t.Helper()
+
+	s, err := New("Bot token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := newfakeGateway()
+	s.GatewayDialer = g
+	s.gateway = "wss://gateway.test"
+	s.ReconnectBackoff = ConstantBackoff{Delay: 10 * time.Millisecond}
+
+	return s, g
+}
+
+func acceptGateway(t *testing.T, g *fakeGateway) *fakeGatewayConn {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := g.Accept(ctx)
+	if err != nil {
+		t.Fatalf("session did not dial the gateway: %v", err)
+	}
+
+	if err := c.Hello(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func nextOp(t *testing.T, c *fakeGatewayConn, op int, timeout time.Duration) *Event {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	e, err := c.Next(ctx)
+	if err != nil {
+		t.Fatalf("waiting for Op %d: %v", op, err)
+	}
+	if e.Operation != op {
+		t.Fatalf("got Op %d, want Op %d", e.Operation, op)
+	}
+
+	return e
+}
+
+func openGatewayTestSession(t *testing.T, s *Session, g *fakeGateway) *fakeGatewayConn {
+	t.Helper()
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- s.Open()
+	}()
+
+	c := acceptGateway(t, g)
+	nextOp(t, c, 2, 5*time.Second)
+
+	if err := c.Ready(&Ready{SessionID: "session", ResumeGatewayURL: testResumeGatewayURL}); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func dispatchAndWait(t *testing.T, s *Session, c *fakeGatewayConn) {
+	t.Helper()
+
+	typing := make(chan struct{}, 1)
+	remove := s.AddHandlerOnce(func(s *Session, e *TypingStart) {
+		typing <- struct{}{}
+	})
+	defer remove()
+
+	if err := c.Dispatch("TYPING_START", &TypingStart{ChannelID: "1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-typing:
+	case <-time.After(5 * time.Second):
+		t.Fatal("dispatch was not handled")
+	}
+}
+
+type resumeData struct {
+	SessionID string `json:"session_id"`
+	Sequence  int64  `json:"seq"`
+}
+
+func expectResume(t *testing.T, s *Session, g *fakeGateway, sequence int64) {
+	t.Helper()
+
+	c := acceptGateway(t, g)
+	e := nextOp(t, c, 6, 5*time.Second)
+
+	var d resumeData
+	if err := e.unmarshal(&d); err != nil {
+		t.Fatal(err)
+	}
+	if d.SessionID != "session" || d.Sequence != sequence {
+		t.Fatalf("resumed session %q at %d, want %q at %d", d.SessionID, d.Sequence, "session", sequence)
+	}
+
+	g.Lock()
+	url := g.URLs[len(g.URLs)-1]
+	g.Unlock()
+	if !strings.HasPrefix(url, testResumeGatewayURL+"/") {
+		t.Fatalf("resumed on %s, want the resume gateway URL", url)
+	}
+
+	if err := c.Resumed(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitForEvent(t *testing.T, ch <-chan interface{}, what string) interface{} {
+	t.Helper()
+
+	select {
+	case e := <-ch:
+		return e
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+
+	return nil
+}
+
+func TestGatewayETF(t *testing.T) {
+	s, g := newGatewayTestSession(t)
+	defer s.Close()
+
+	s.Encoding = GatewayEncodingETF
+	g.Encoding = GatewayEncodingETF
+
+	c := openGatewayTestSession(t, s, g)
+	dispatchAndWait(t, s, c)
+
+	g.Lock()
+	url := g.URLs[0]
+	g.Unlock()
+	if !strings.Contains(url, "encoding=etf") {
+		t.Fatalf("dialed %s, want an ETF gateway URL", url)
+	}
+
+	if err := c.Reconnect(); err != nil {
+		t.Fatal(err)
+	}
+	expectResume(t, s, g, 2)
+}
+
+func TestGatewayReconnectResumes(t *testing.T) {
+	s, g := newGatewayTestSession(t)
+	defer s.Close()
+
+	requested := make(chan interface{}, 1)
+	s.AddHandler(func(s *Session, e *ReconnectRequested) {
+		requested <- e
+	})
+
+	c := openGatewayTestSession(t, s, g)
+	dispatchAndWait(t, s, c)
+
+	if err := c.Reconnect(); err != nil {
+		t.Fatal(err)
+	}
+
+	waitForEvent(t, requested, "ReconnectRequested")
+	expectResume(t, s, g, 2)
+}
+
+func TestGatewayResumableInvalidSessionResumes(t *testing.T) {
+	s, g := newGatewayTestSession(t)
+	defer s.Close()
+
+	invalid := make(chan interface{}, 1)
+	s.AddHandler(func(s *Session, e *InvalidSession) {
+		invalid <- e
+	})
+
+	c := openGatewayTestSession(t, s, g)
+	dispatchAndWait(t, s, c)
+
+	if err := c.InvalidSession(true); err != nil {
+		t.Fatal(err)
+	}
+
+	if e := waitForEvent(t, invalid, "InvalidSession").(*InvalidSession); !e.Resumable {
+		t.Fatal("InvalidSession was not resumable")
+	}
+	expectResume(t, s, g, 2)
+}
+
+func TestGatewayInvalidSessionIdentifies(t *testing.T) {
+	s, g := newGatewayTestSession(t)
+	defer s.Close()
+
+	c := openGatewayTestSession(t, s, g)
+	dispatchAndWait(t, s, c)
+
+	if err := c.InvalidSession(false); err != nil {
+		t.Fatal(err)
+	}
+
+	s.RLock()
+	lastAck := s.LastHeartbeatAck
+	s.RUnlock()
+
+	if err := c.Send(11, "", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for {
+		s.RLock()
+		acked := s.LastHeartbeatAck.After(lastAck)
+		s.RUnlock()
+		if acked {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("waiting to identify blocked the gateway reader")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	nextOp(t, c, 2, 10*time.Second)
+
+	if rs := s.ResumeState(); rs.SessionID != "" || rs.Sequence != 0 {
+		t.Fatalf("session was not reset before identifying: %+v", rs)
+	}
+}
+
+func TestGatewayResumableCloseResumes(t *testing.T) {
+	s, g := newGatewayTestSession(t)
+	defer s.Close()
+
+	c := openGatewayTestSession(t, s, g)
+	dispatchAndWait(t, s, c)
+
+	if err := c.CloseWithCode(int(GatewayCloseUnknownError), "unknown error"); err != nil {
+		t.Fatal(err)
+	}
+
+	expectResume(t, s, g, 2)
+}
+
+func TestGatewayNonResumableCloseIdentifies(t *testing.T) {
+	s, g := newGatewayTestSession(t)
+	defer s.Close()
+
+	c := openGatewayTestSession(t, s, g)
+	dispatchAndWait(t, s, c)
+
+	if err := c.CloseWithCode(int(GatewayCloseInvalidSeq), "invalid seq"); err != nil {
+		t.Fatal(err)
+	}
+
+	c = acceptGateway(t, g)
+	nextOp(t, c, 2, 5*time.Second)
+}
+
+func TestGatewayFatalCloseDoesNotReconnect(t *testing.T) {
+	s, g := newGatewayTestSession(t)
+	defer s.Close()
+
+	fatal := make(chan interface{}, 1)
+	s.AddHandler(func(s *Session, e *GatewayFatalClose) {
+		fatal <- e
+	})
+
+	c := openGatewayTestSession(t, s, g)
+
+	if err := c.CloseWithCode(int(GatewayCloseAuthenticationFailed), "authentication failed"); err != nil {
+		t.Fatal(err)
+	}
+
+	if e := waitForEvent(t, fatal, "GatewayFatalClose").(*GatewayFatalClose); e.Code != GatewayCloseAuthenticationFailed {
+		t.Fatalf("got close code %d, want %d", e.Code, GatewayCloseAuthenticationFailed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := g.Accept(ctx); err == nil {
+		t.Fatal("session reconnected after a fatal close code")
+	}
+
+	if rs := s.ResumeState(); rs.SessionID != "" {
+		t.Fatalf("session was not reset after a fatal close: %+v", rs)
+	}
+}
+
+func TestFetchGuildMembersSetsGuildID(t *testing.T) {
+	s, g := newGatewayTestSession(t)
+	defer s.Close()
+
+	seen := make(chan interface{}, 1)
+	s.AddHandler(func(s *Session, c *GuildMembersChunk) {
+		seen <- c.Members[0].GuildID
+	})
+
+	c := openGatewayTestSession(t, s, g)
+
+	type fetchResult struct {
+		result *GuildMembersResult
+		err    error
+	}
+	done := make(chan fetchResult, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		result, err := s.FetchGuildMembers(ctx, "1", "", nil, 0, false)
+		done <- fetchResult{result, err}
+	}()
+
+	e := nextOp(t, c, 8, 5*time.Second)
+	var d requestGuildMembersData
+	if err := e.unmarshal(&d); err != nil {
+		t.Fatal(err)
+	}
+
+	err := c.Dispatch("GUILD_MEMBERS_CHUNK", &GuildMembersChunk{
+		GuildID:    "1",
+		Members:    []*Member{{User: &User{ID: "2"}}},
+		ChunkIndex: 0,
+		ChunkCount: 1,
+		Nonce:      d.Nonce,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id := waitForEvent(t, seen, "GuildMembersChunk").(string); id != "1" {
+		t.Fatalf("handler saw member guild ID %q, want %q", id, "1")
+	}
+
+	r := <-done
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+	if len(r.result.Members) != 1 || r.result.Members[0].GuildID != "1" {
+		t.Fatalf("unexpected result %+v", r.result)
+	}
+}